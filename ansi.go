@@ -0,0 +1,366 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/colors"
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// FromANSI converts text containing ANSI "Select Graphic Rendition" (SGR)
+// escape sequences into equivalent decor notation. Basic, bright, 256-color
+// and direct (24-bit) color forms are supported, as are sgr0 resets, the
+// attributes supported by decor notation and OSC 8 hyperlinks. Colors are
+// rendered using color names wherever possible and any '@' or '$' characters
+// in text are escaped.
+//
+// An error is returned if text contains a malformed or unsupported escape
+// sequence (e.g. one for cursor movement) or a hyperlink URI containing every
+// possible argument delimiter.
+func FromANSI(text string) (string, error) {
+	c := &ansiConverter{}
+
+	for pos := 0; pos < len(text); {
+		i := strings.IndexByte(text[pos:], '\x1b')
+		if i == -1 {
+			c.text(text[pos:])
+			break
+		}
+
+		c.text(text[pos : pos+i])
+		pos += i
+
+		n, err := c.escape(text[pos:])
+		if err != nil {
+			return "", fmt.Errorf("%w at pos %d", err, pos)
+		}
+
+		pos += n
+	}
+
+	return c.out.String(), nil
+}
+
+type ansiConverter struct {
+	out strings.Builder
+
+	// open holds the designators for attributes currently in effect, in
+	// the order they were started.
+	open []string
+}
+
+func (c *ansiConverter) text(s string) {
+	c.out.WriteString(escaper.Replace(s))
+}
+
+// escape converts the escape sequence at the start of s, returning its
+// length.
+func (c *ansiConverter) escape(s string) (int, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("incomplete escape sequence")
+	}
+
+	switch s[1] {
+	case '[':
+		// A Control Sequence Introducer; parameter bytes are followed by a
+		// single final byte.
+		j := strings.IndexFunc(s[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+		if j == -1 {
+			return 0, fmt.Errorf("incomplete escape sequence %q", s)
+		}
+
+		if s[2+j] != 'm' {
+			return 0, fmt.Errorf("unsupported escape sequence %q", s[:3+j])
+		}
+
+		if err := c.sgr(s[2 : 2+j]); err != nil {
+			return 0, fmt.Errorf("%w in %q", err, s[:3+j])
+		}
+
+		return 3 + j, nil
+
+	case ']':
+		return c.osc(s)
+
+	case '(':
+		// Character set designation, as emitted by some terminals' sgr0;
+		// this has no decor equivalent and is simply dropped.
+		if len(s) < 3 {
+			return 0, fmt.Errorf("incomplete escape sequence %q", s)
+		}
+		return 3, nil
+
+	default:
+		return 0, fmt.Errorf("unsupported escape sequence %q", s[:2])
+	}
+}
+
+// osc converts the OSC 8 hyperlink sequence at the start of s, returning
+// its length.
+func (c *ansiConverter) osc(s string) (int, error) {
+	body, n := s[2:], 0
+
+	switch i := strings.IndexAny(body, "\a\x1b"); {
+	case i == -1:
+		return 0, fmt.Errorf("incomplete escape sequence %q", s)
+	case body[i] == '\a':
+		body, n = body[:i], 2+i+1
+	case strings.HasPrefix(body[i:], "\x1b\\"):
+		body, n = body[:i], 2+i+2
+	default:
+		return 0, fmt.Errorf("unterminated escape sequence %q", s[:2+i])
+	}
+
+	// Hyperlinks are of the form "8;params;URI"
+	parts := strings.SplitN(body, ";", 3)
+	if len(parts) != 3 || parts[0] != "8" {
+		return 0, fmt.Errorf("unsupported escape sequence %q", s[:n])
+	}
+
+	c.stop("@l")
+
+	if uri := parts[2]; uri != "" {
+		lnk := item.StartItem(item.LINK)
+		lnk.Text = uri
+
+		// The URI must be enclosed by delimiters it doesn't itself contain.
+		ss := series.New()
+		if err := ss.Parse(lnk.Notation()); err != nil || ss.Len() != 1 || ss.Front().Text != uri {
+			return 0, fmt.Errorf("hyperlink URI %q cannot be represented in decor notation", uri)
+		}
+
+		c.start(lnk.Notation())
+	}
+
+	return n, nil
+}
+
+// sgrStarts and sgrStops map simple SGR parameters to the decor designators
+// they start or stop.
+var (
+	sgrStarts = map[int]string{
+		1: "@B",
+		2: "@D",
+		3: "@I",
+		4: "@U",
+		5: "@N",
+		7: "@R",
+		8: "@H",
+		9: "@X",
+	}
+
+	sgrStops = map[int][]string{
+		22: {"@b", "@d"},
+		23: {"@i"},
+		24: {"@u"},
+		25: {"@n"},
+		27: {"@r"},
+		28: {"@h"},
+		29: {"@x"},
+		39: {"@f"},
+		49: {"@k"},
+		59: {"@c"},
+	}
+
+	// extColorDesignators maps extended color parameters to their decor
+	// designators.
+	extColorDesignators = map[int]string{38: "F", 48: "K", 58: "C"}
+
+	// ulStyleNames is indexed by the sub-parameter of SGR 4 (e.g. "4:3").
+	ulStyleNames = []string{"", "single", "double", "curly", "dotted", "dashed"}
+)
+
+// sgr converts the parameters of a single SGR sequence.
+func (c *ansiConverter) sgr(params string) error {
+	if strings.IndexFunc(params, func(r rune) bool { return (r < '0' || r > '9') && r != ';' && r != ':' }) != -1 {
+		return fmt.Errorf("malformed parameters")
+	}
+
+	fields := strings.Split(params, ";")
+
+	for i := 0; i < len(fields); i++ {
+		// Sub-parameters are separated by colons (e.g. "4:3" or "38:5:n")
+		sub := strings.Split(fields[i], ":")
+		p := atoi(sub[0])
+
+		switch {
+		case p == 0:
+			c.reset()
+
+		case p == 4 && len(sub) > 1:
+			switch style := atoi(sub[1]); {
+			case style == 0:
+				c.stop("@u")
+			case style == 1:
+				c.start("@U")
+			case style > 1 && style < len(ulStyleNames):
+				c.start("@U{" + ulStyleNames[style] + "}")
+			default:
+				return fmt.Errorf("unsupported underline style %d", style)
+			}
+
+		case sgrStarts[p] != "":
+			c.start(sgrStarts[p])
+
+		case sgrStops[p] != nil:
+			c.stop(sgrStops[p]...)
+
+		case p >= 30 && p <= 37:
+			c.color("F", strconv.Itoa(p-30))
+		case p >= 90 && p <= 97:
+			c.color("F", strconv.Itoa(p-90+8))
+		case p >= 40 && p <= 47:
+			c.color("K", strconv.Itoa(p-40))
+		case p >= 100 && p <= 107:
+			c.color("K", strconv.Itoa(p-100+8))
+
+		case p == 38 || p == 48 || p == 58:
+			args := sub[1:]
+			if len(sub) == 1 {
+				// Semicolon separated; consume the following fields.
+				args = fields[i+1:]
+			}
+
+			clr, n, err := extColor(args)
+			if err != nil {
+				return err
+			}
+
+			if len(sub) == 1 {
+				i += n
+			}
+
+			c.color(extColorDesignators[p], clr)
+
+		default:
+			return fmt.Errorf("unsupported parameter %q", fields[i])
+		}
+	}
+
+	return nil
+}
+
+// extColor interprets args as the arguments for an extended color parameter
+// (i.e. "5;n" or "2;r;g;b") returning the color's decor name or direct color
+// specification along with the number of arguments consumed.
+func extColor(args []string) (string, int, error) {
+	if len(args) == 0 {
+		return "", 0, fmt.Errorf("missing color arguments")
+	}
+
+	switch args[0] {
+	case "5":
+		if len(args) < 2 || !byteArgs(args[1]) {
+			return "", 0, fmt.Errorf("malformed color arguments")
+		}
+		return strconv.Itoa(atoi(args[1])), 2, nil
+
+	case "2":
+		// Some emitters include an (empty) color space identifier when
+		// using colons; e.g. "38:2::r:g:b".
+		rgb := args[1:]
+		if len(rgb) == 4 && rgb[0] == "" {
+			rgb = rgb[1:]
+		}
+
+		if len(rgb) < 3 || !byteArgs(rgb[:3]...) {
+			return "", 0, fmt.Errorf("malformed color arguments")
+		}
+
+		v := [3]uint8{uint8(atoi(rgb[0])), uint8(atoi(rgb[1])), uint8(atoi(rgb[2]))}
+		n := len(args) - len(rgb) + 3
+
+		// Prefer a name from the 256 color palette (not including the
+		// terminal dependent basic colors) if there's an exact match.
+		for num := 16; num < len(colors.Values); num++ {
+			if colors.Values[num] == v {
+				return strconv.Itoa(num), n, nil
+			}
+		}
+
+		return color.RGB{R: v[0], G: v[1], B: v[2]}.String(), n, nil
+
+	default:
+		return "", 0, fmt.Errorf("unsupported color type %q", args[0])
+	}
+}
+
+// color starts the color attribute designated by d, replacing any color of
+// the same type already in effect. If clr is a color number, its name is
+// used instead.
+func (c *ansiConverter) color(d string, clr string) {
+	if n, err := strconv.Atoi(clr); err == nil {
+		clr = color.Name(uint8(n))
+	}
+
+	c.start("@" + d + "{" + clr + "}")
+}
+
+// start emits the given designator, unless it is already in effect. Any
+// other designator of the same type in effect is stopped first.
+func (c *ansiConverter) start(d string) {
+	for _, o := range c.open {
+		if o == d {
+			return
+		}
+	}
+
+	c.stop(strings.ToLower(d[:2]))
+
+	c.out.WriteString(d)
+	c.open = append(c.open, d)
+}
+
+// stop emits each of the given (stop) designators whose attribute is in
+// effect, in the reverse order they were started.
+func (c *ansiConverter) stop(ds ...string) {
+	for i := len(c.open) - 1; i >= 0; i-- {
+		o := strings.ToLower(c.open[i][:2])
+		for _, d := range ds {
+			if o == d {
+				c.out.WriteString(d)
+				c.open = append(c.open[:i], c.open[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// reset stops all attributes currently in effect.
+func (c *ansiConverter) reset() {
+	for i := len(c.open) - 1; i >= 0; i-- {
+		c.out.WriteString(strings.ToLower(c.open[i][:2]))
+	}
+	c.open = c.open[:0]
+}
+
+// atoi returns the integer value of s, with the empty string (i.e. a default
+// SGR parameter) being zero, or -1 if s is not a valid integer.
+func atoi(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// byteArgs returns true if each of args is a valid integer from 0 to 255.
+func byteArgs(args ...string) bool {
+	for _, a := range args {
+		if n := atoi(a); n < 0 || n > 255 {
+			return false
+		}
+	}
+	return true
+}
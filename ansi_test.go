@@ -0,0 +1,76 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestFromANSI(t *testing.T) {
+	cases := []struct {
+		ansi string
+		want string
+	}{
+		{"plain user@host $HOME", "plain user@@host $$HOME"},
+		{"\x1b[1mbold\x1b[0m", "@Bbold@b"},
+		{"\x1b[1;3;38;5;44mx\x1b[23my\x1b[m", "@B@I@F{DarkTurquoise}x@iy@f@b"},
+		{"\x1b[31mred\x1b[34mblue\x1b[39m", "@F{RED}red@f@F{BLUE}blue@f"},
+		{"\x1b[91;100mx\x1b[49;39m", "@F{BOLD_RED}@K{BOLD_BLACK}x@k@f"},
+		{"\x1b[38;2;255;135;0mx\x1b[38;2;1;2;3my", "@F{DarkOrange}x@f@F{#010203}y"},
+		{"\x1b[38:2::1:2:3;1mx", "@F{#010203}@Bx"},
+		{"\x1b[4:3m\x1b[58;5;196mtypo\x1b[59;4:0m", "@U{curly}@C{Red1}typo@c@u"},
+		{"\x1b[2;1mx\x1b[22my", "@D@Bx@b@dy"},
+		{"\x1b[7;9mx\x1b(B\x1b[m", "@R@Xx@x@r"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "@L{https://example.com}link@l"},
+		{"\x1b]8;id=1;http://a.b\alink\x1b]8;;\a", "@L{http://a.b}link@l"},
+		{"\x1b]8;;http://a/}b\x1b\\x\x1b]8;;\x1b\\", "@L(http://a/}b)x@l"},
+	}
+
+	for _, tc := range cases {
+		if got, err := FromANSI(tc.ansi); err != nil || got != tc.want {
+			t.Errorf("FromANSI(%q) == (%q, %v); Wanted (%q, nil)", tc.ansi, got, err, tc.want)
+		}
+	}
+
+	errs := []struct {
+		ansi string
+		want string
+	}{
+		{"ab\x1b[2J", `unsupported escape sequence "\x1b[2J" at pos 2`},
+		{"\x1b[38;5m", `malformed color arguments in "\x1b[38;5m" at pos 0`},
+		{"\x1b[31", `incomplete escape sequence "\x1b[31" at pos 0`},
+		{"x\x1b[65m", `unsupported parameter "65" in "\x1b[65m" at pos 1`},
+		{"\x1b]8;;{}()[]<>|+:\a", `hyperlink URI "{}()[]<>|+:" cannot be represented in decor notation at pos 0`},
+	}
+
+	for _, tc := range errs {
+		if got, err := FromANSI(tc.ansi); err == nil || err.Error() != tc.want {
+			t.Errorf("FromANSI(%q) == (%q, %v); Wanted error %q", tc.ansi, got, err, tc.want)
+		}
+	}
+}
+
+func TestFromANSIRoundTrip(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{
+		"@B@F{44}@Iuser@i@f@F{Orchid1}@@@f@F{Green3}host@f@b",
+		"@K{Grey37}@Ustatus:@u @F{Red1}$$fail@f@k",
+	} {
+		want, err := d.Format(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dtext, err := FromANSI(want)
+		if err != nil {
+			t.Errorf("FromANSI(%q) error: %v", want, err)
+			continue
+		}
+
+		if got, err := d.Format(dtext); err != nil || got != want {
+			t.Errorf("Format(FromANSI(Format(%q))) == (%q, %v); Wanted (%q, nil)", text, got, err, want)
+		}
+	}
+}
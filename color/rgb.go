@@ -0,0 +1,122 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"toolman.org/terminal/decor/internal/colors"
+)
+
+// RGB is a 24-bit color value comprised of 8-bit red, green and blue
+// components.
+type RGB struct {
+	R, G, B uint8
+}
+
+// String returns the receiver in hexadecimal notation (e.g. "#ff8800").
+func (c RGB) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Value returns the RGB value the xterm color table assigns to the given
+// color number.
+func Value(num uint8) RGB {
+	v := colors.Values[num]
+	return RGB{v[0], v[1], v[2]}
+}
+
+// ParseRGB parses a direct color specification into an RGB value. The
+// following forms are supported:
+//
+//	#rrggbb          - Hexadecimal (e.g. "#ff8800")
+//	#rgb             - Short hexadecimal (e.g. "#f80")
+//	rgb(r,g,b)       - Decimal components (e.g. "rgb(255,136,0)")
+//
+// An error is returned if s is not in one of these forms.
+func ParseRGB(s string) (RGB, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case strings.HasPrefix(s, "#"):
+		return parseHex(s[1:])
+
+	case strings.HasPrefix(strings.ToLower(s), "rgb(") && strings.HasSuffix(s, ")"):
+		return parseDecimal(s[4 : len(s)-1])
+
+	default:
+		return RGB{}, fmt.Errorf("invalid color specification: %q", s)
+	}
+}
+
+func parseHex(s string) (RGB, error) {
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+
+	if len(s) != 6 {
+		return RGB{}, fmt.Errorf("invalid hex color: %q", "#"+s)
+	}
+
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid hex color: %q", "#"+s)
+	}
+
+	return RGB{uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+}
+
+func parseDecimal(s string) (RGB, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return RGB{}, fmt.Errorf("invalid rgb color: %q", "rgb("+s+")")
+	}
+
+	var v [3]uint8
+	for i, p := range parts {
+		n, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+		if err != nil {
+			return RGB{}, fmt.Errorf("invalid rgb color: %q", "rgb("+s+")")
+		}
+		v[i] = uint8(n)
+	}
+
+	return RGB{v[0], v[1], v[2]}, nil
+}
+
+// Nearest returns the color number from the xterm color table whose value is
+// closest to c. The search is limited to color numbers less than limit and,
+// for a limit greater than 16, the 16 system colors are excluded since their
+// actual values are commonly altered by terminal themes.
+func Nearest(c RGB, limit int) uint8 {
+	if limit > len(colors.Values) {
+		limit = len(colors.Values)
+	}
+
+	first := 0
+	if limit > 16 {
+		first = 16
+	}
+
+	best, bdist := first, -1
+	for n := first; n < limit; n++ {
+		if d := distance(c, Value(uint8(n))); bdist < 0 || d < bdist {
+			best, bdist = n, d
+		}
+	}
+
+	return uint8(best)
+}
+
+// distance returns a weighted (squared) distance between two colors using
+// the "redmean" approximation of human color perception.
+func distance(a, b RGB) int {
+	rm := (int(a.R) + int(b.R)) / 2
+	dr := int(a.R) - int(b.R)
+	dg := int(a.G) - int(b.G)
+	db := int(a.B) - int(b.B)
+
+	return (((512 + rm) * dr * dr) >> 8) + 4*dg*dg + (((767 - rm) * db * db) >> 8)
+}
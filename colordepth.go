@@ -0,0 +1,111 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+
+	"github.com/xo/terminfo"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/colors"
+)
+
+// DirectColors is the value returned by a Decorator's Colors method for
+// terminals supporting direct (24-bit) color.
+const DirectColors = 1 << 24
+
+const (
+	ansiFG256 = "\x1b[38;5;%dm"
+	ansiBG256 = "\x1b[48;5;%dm"
+)
+
+// Colors returns the number of colors supported by the receiver's terminal
+// type. This will be one of DirectColors, 256, 16, 8 or 0 (for monochrome
+// terminals or when colors are disabled by the receiver's ColorPolicy).
+func (d *Decorator) Colors() int {
+	if d != nil {
+		return d.colors
+	}
+	return 0
+}
+
+// terminfoDepth returns the color depth for the given terminfo entry based
+// on its "colors" capability and the "Tc" or "RGB" extended capabilities
+// (which indicate support for direct color).
+func terminfoDepth(ti *terminfo.Terminfo) int {
+	if hasExtBool(ti, "Tc") || hasExtBool(ti, "RGB") {
+		return DirectColors
+	}
+
+	switch n := ti.Num(terminfo.MaxColors); {
+	case n >= 256:
+		return 256
+	case n >= 16:
+		return 16
+	case n >= 8:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// colortermDepth returns the color depth indicated by the given $COLORTERM
+// value, or 0 if nothing can be discerned from it.
+func colortermDepth(colorterm string) int {
+	switch colorterm {
+	case "truecolor", "24bit":
+		return DirectColors
+	default:
+		return 0
+	}
+}
+
+// setColors populates the receiver's foreground and background color tables
+// for a terminal supporting the given number of colors. Color numbers beyond
+// this depth are mapped to the nearest supported color. If depth exceeds the
+// number of colors declared by ti, the excess color numbers are emitted as
+// ANSI 256-color sequences.
+func (d *Decorator) setColors(ti *terminfo.Terminfo, depth int) {
+	d.colors = depth
+	maxc := ti.Num(terminfo.MaxColors)
+
+	for n := range colors.Names {
+		switch {
+		case depth == 0:
+			d.fg[n] = ""
+			d.bg[n] = ""
+
+		case n < depth && n < maxc:
+			d.fg[n] = ti.Printf(terminfo.SetAForeground, n)
+			d.bg[n] = ti.Printf(terminfo.SetABackground, n)
+
+		case n < depth:
+			d.fg[n] = fmt.Sprintf(ansiFG256, n)
+			d.bg[n] = fmt.Sprintf(ansiBG256, n)
+
+		default:
+			m := int(color.Nearest(color.Value(uint8(n)), depth))
+			d.fg[n] = ti.Printf(terminfo.SetAForeground, m)
+			d.bg[n] = ti.Printf(terminfo.SetABackground, m)
+		}
+	}
+}
+
+// paletteSize returns the number of entries from the receiver's color
+// tables that are usable for nearest color matching.
+func (d *Decorator) paletteSize() int {
+	if d.colors > len(d.fg) {
+		return len(d.fg)
+	}
+	return d.colors
+}
+
+// defColor returns code (which resets the default foreground or background
+// color) unless the receiver's terminal does not support colors.
+func (d *Decorator) defColor(code string) string {
+	if d.colors == 0 {
+		return ""
+	}
+	return code
+}
@@ -4,30 +4,32 @@
 Package decor provides facilities for decorating a string of characters
 with display attributes for the current (or specified) terminal type. This
 is done using a notation inspired by (but slightly different from) Zsh
-prompt formatting.  Supported attributes are bold, italic, and/or underlined
-characters as well as 256-color support for foreground and background
-colors.
+prompt formatting.  Supported attributes are bold, dim, italic, underlined,
+reverse, blinking, strikethrough, invisible and/or standout characters as well
+as 256-color (or direct color) support for foreground and background colors.
 
 As a simple example, the numbered markings in the decor notated string argument
 to the Format method here:
 
 	// error handling elided
 	d, _ := decor.New()
-	s, _ := d.Format("@B@F{44}@Iuser@i@F{Orchid1}@@@F{Green3}host@f@b")
-	//                ^ ^     ^     ^ ^          ^ ^            ^ ^
-	//                1 2     3     4 5          6 7            8 9
+	s, _ := d.Format("@B@F{44}@Iuser@i@f@F{Orchid1}@@@f@F{Green3}host@f@b")
+	//                ^ ^     ^     ^ ^ ^          ^ ^ ^             ^ ^
+	//                1 2     3     4 5 6          7 8 9            10 11
 
 ...each have the following meaning.
 
-	#1. Start Bold Text
-	#2. Start Foreground Color #44 (DarkTurquoise)
-	#3. Start Italics Text
-	#4. End Italics Text
-	#5. Start Foreground Color Orchid1 (#213)
-	#6. A Literal '@' Character
-	#7. Begin Foreground Color Green3 (#40)
-	#8. End Foreground Color
-	#9. End Bold Text
+	#1.  Start Bold Text
+	#2.  Start Foreground Color #44 (DarkTurquoise)
+	#3.  Start Italics Text
+	#4.  End Italics Text
+	#5.  End Foreground Color
+	#6.  Start Foreground Color Orchid1 (#213)
+	#7.  A Literal '@' Character
+	#8.  End Foreground Color
+	#9.  Start Foreground Color Green3 (#40)
+	#10. End Foreground Color
+	#11. End Bold Text
 
 Therefore, the value assigned to s would be the string "user@host" - formatted
 for the current terminal - in all bold text with "user" displayed in italics
@@ -36,7 +38,10 @@ and foreground color DarkTurquoise, the '@' character with color Orchid1, and
 
 Or more specifically, for TERM="xterm-256color", the value of s would be:
 
-	"\x1b[1m\x1b[38;5;44m\x1b[3muser\x1b[23m\x1b[38;5;213m@\x1b[38;5;40mhost\x1b(B\x1b[m\x1b(B\x1b[m"
+	"\x1b[1m\x1b[38;5;44m\x1b[3muser\x1b[23m\x1b[38;5;213m@\x1b[38;5;40mhost\x1b[39m\x1b(B\x1b[m"
+
+Note that redundant attribute changes (such as ending a foreground color that
+is immediately replaced by another) are omitted from the output.
 
 # Attributes
 
@@ -47,14 +52,77 @@ to begin the attribute or a lower case letter to end that attribute.
 The full list of supported attribute designators is as follows:
 
 	@B (@b) - Start (stop) boldface mode
+	@D (@d) - Start (stop) dim (half-bright) mode
 	@I (@i) - Start (stop) italics mode
 	@U (@u) - Start (stop) underline mode
+	@R (@r) - Start (stop) reverse video mode
+	@N (@n) - Start (stop) blinking mode
+	@X (@x) - Start (stop) strikethrough mode
+	@H (@h) - Start (stop) invisible (hidden) mode
+	@S (@s) - Start (stop) standout mode
 	@F (@f) - Start (stop) specified foreground color
 	@K (@k) - Start (stop) specified background color
+	@C (@c) - Start (stop) specified underline color
+	@L (@l) - Start (stop) hyperlink to specified URL
+
+Note that terminals lacking a capability to stop a specific attribute (e.g.
+dim, reverse, blink or invisible) will have all attributes turned off and
+those still in effect will be turned back on.
+
+Attributes may be nested. Stopping an attribute restores whatever was in
+effect before it was started rather than reverting to the terminal's default.
+For example, in the string "@F{Red}a@F{Blue}b@fc@f" the character "c" is
+displayed in red.
+
+# Underline Styles
+
+Terminals supporting the extended "Smulx" terminfo capability may also display
+underlines in one of several styles by following the @U designator with a
+style name wrapped in braces. The supported styles are "single", "double",
+"curly", "dotted" and "dashed" (e.g. "@U{curly}misspelt@u"). For terminals
+lacking this capability, a plain underline is used instead. Braces holding
+anything other than a style name are not special; they're displayed as
+underlined text (e.g. "@U{note}@u").
+
+Similarly, the color of an underline may be set (independently from the
+foreground color) using the @C designator for terminals supporting the
+extended "Setulc" capability; for other terminals it is ignored.
+
+# Color Policy
+
+Decorators created by New honor the following environment variables
+conventionally used to control whether command line tools display colors:
+
+	NO_COLOR        - If set (and non-empty), colors are disabled
+	CLICOLOR        - If set to "0", colors are disabled
+	CLICOLOR_FORCE  - If set (and not "0"), overrides CLICOLOR and
+	                  causes NewFor to decorate non-terminal output
+
+Note that NO_COLOR takes precedence over CLICOLOR_FORCE and that disabling
+colors does not affect other attributes such as bold or underline. The policy
+applied is reported by the Decorator's ColorPolicy method.
+
+# Hyperlinks
+
+Text may be displayed as a clickable hyperlink (using the OSC 8 escape
+sequence) by wrapping it with the @L and @l designators, where @L is followed
+by the link's target URL wrapped in braces (as with colors); for example,
+"@L{https://example.com}Example@l". Hyperlinks are omitted for terminals
+not known to support them (see the Hyperlinks Option).
+
+# Shell Prompts
+
+When decorating a shell prompt string, terminal codes must be marked as
+having no width or the shell will miscalculate the prompt's length (and
+wrap lines incorrectly). The Prompt method (or the PromptShell Option)
+wraps each run of terminal codes with the markers required by the given
+shell; "%{...%}" for Zsh or "\[...\]" for Bash. For example:
+
+	ps1, _ := d.Prompt(decor.Bash, `@F{Green3}\u@@\h@f:\w\$$ `)
 
 # Color Designations
 
-The start-color designators (@F and @K) are then followed by a color name
+The start-color designators (@F, @K and @C) are then followed by a color name
 or number wrapped in braces (such as "@F{DodgerBlue}"). Note that the braces
 surrounding the color name (or number) are not limited to '{' and '}'; these
 can be any matching pair of brace-like characters (i.e. "<color>", "[color]"
@@ -64,6 +132,23 @@ characters agree (e.g. "@F+color+").
 See package toolman.org/terminal/decor/color for a complete list of supported
 color names.
 
+Colors may also be specified directly as 24-bit RGB values using either
+hexadecimal notation (e.g. "@F{#ff8800}" or "@F{#f80}") or decimal components
+(e.g. "@K{rgb(255,136,0)}"). For terminals supporting direct color (as
+indicated by the "Tc" or "RGB" terminfo capabilities or a $COLORTERM value of
+"truecolor" or "24bit") these are emitted as-is; otherwise, the nearest color
+available to the terminal is used.
+
+# Color Depth
+
+Not all terminals support 256 colors. A Decorator determines the number of
+colors supported by its terminal from the terminfo "colors" capability (and,
+for New, the $COLORTERM environment variable) and any color beyond the
+terminal's capabilities is mapped to the nearest color it does support. This
+allows a single decor string to display reasonably on direct color, 256-color,
+16-color and 8-color terminals alike. For monochrome terminals, colors are
+omitted entirely.
+
 # Templates
 
 In addition to simple string decoration, this package also supports variable
@@ -94,6 +179,37 @@ optimized away.
 For example, if a template specifies a foreground color of "SpringGreen" and
 a variable changes the foreground color to "DarkCyan", the foreground color
 will be restored to "SpringGreen" once the variable has been expanded.
+
+Variable references may also provide alternate text depending on whether the
+variable has a value, in the manner of shell parameter expansion. Here, as
+with the shell, a variable with an empty value is considered to be unset.
+
+	${name:-default}    - Expands to default if name is unset
+	${name:+alternate}  - Expands to alternate if name is set (or else nothing)
+	${name:?message}    - Expands to an error with message if name is unset
+
+The default and alternate text may itself contain attribute designators and
+other variable references (e.g. "${Branch:+ @F{Green3}${Branch}@f}").
+
+A variable name beginning with a '$' refers to an environment variable
+instead of a value provided to Expand (e.g. "${$HOME}" or "${$EDITOR:-vi}").
+Unlike other variables, the values of environment variables are always
+displayed literally; any decor notation they contain is not interpreted.
+See the Environment Option for supplying an alternate environment.
+
+# Other Output Formats
+
+Decor-notated text (and expanded templates) may also be rendered for display
+somewhere other than a terminal. An HTML value renders text as HTML <span>
+elements styled with either inline CSS or CSS classes while an SVG value
+renders a standalone SVG image resembling a terminal's display of the text.
+A Tmux value renders text using the style syntax of tmux status lines (e.g.
+"#[fg=colour44,bold]") so the same decor strings may drive both a shell
+prompt and a tmux status bar; a Screen value does the same using the string
+escapes of GNU screen (e.g. "%{=b dc}").
+
+Each of these is a Renderer, which may be passed to a Decorator's Render
+method to parse text according to the Decorator's Options (e.g. Strict).
 */
 package decor
 
@@ -119,30 +235,83 @@ type Decorator struct {
 	fg    []string
 	bg    []string
 	debug int
+
+	// colors is the number of colors supported by the terminal (see
+	// the Colors method).
+	colors int
+
+	// policy is the color policy derived from the environment (see the
+	// ColorPolicy method).
+	policy ColorPolicy
+
+	// plain indicates that no terminal codes should be emitted at all
+	// (see NewFor).
+	plain bool
+
+	// ulstyle maps underline style names to their terminal codes while
+	// setulc holds the terminal's capability for setting the underline
+	// color (if any) and ul its table of underline colors.
+	ulstyle map[string]string
+	setulc  []byte
+	ul      []string
+
+	// hyperlinks indicates whether the terminal supports OSC 8 hyperlinks
+	// (see the Hyperlinks Option).
+	hyperlinks bool
+
+	// mergeSGR indicates whether consecutive SGR sequences should be
+	// combined (see the MergeSGR Option).
+	mergeSGR bool
+
+	// strict indicates whether unknown designators, colors and underline
+	// styles should be rejected (see the Strict Option).
+	strict bool
+
+	// shell is the shell whose zero-width markers wrap each run of
+	// terminal codes (see the PromptShell Option).
+	shell Shell
+
+	// lookupEnv, if not nil, replaces os.LookupEnv for resolving
+	// environment variable references (see the Environment Option).
+	lookupEnv func(string) (string, bool)
 }
 
 // New returns a new *Decorator for the terminal type specified by the $TERM
 // environment variable, or nil and an error if a new *Decorator cannot be
-// created.
-func New() (*Decorator, error) {
+// created. The returned Decorator's behavior may be altered by zero or more
+// Options.
+func New(opts ...Option) (*Decorator, error) {
 	ti, err := terminfo.LoadFromEnv()
 	if err != nil {
 		return nil, err
 	}
 
-	return newDecorator(os.Getenv("TERM"), ti), nil
+	d := newDecorator(os.Getenv("TERM"), ti)
+
+	if n := colortermDepth(os.Getenv("COLORTERM")); n > d.colors {
+		d.setColors(ti, n)
+	}
+
+	d.applyPolicy(ti, colorPolicy(os.Getenv))
+
+	if hyperlinkEnv(os.Getenv) {
+		d.hyperlinks = true
+	}
+
+	return d.apply(opts), nil
 }
 
 // Load returns a new *Decorator for the specified terminal type (ignoring the
 // current environment) or nil and an error if a new *Decorator cannot be
-// created.
-func Load(term string) (*Decorator, error) {
+// created. The returned Decorator's behavior may be altered by zero or more
+// Options.
+func Load(term string, opts ...Option) (*Decorator, error) {
 	ti, err := terminfo.Load(term)
 	if err != nil {
 		return nil, err
 	}
 
-	return newDecorator(term, ti), nil
+	return newDecorator(term, ti).apply(opts), nil
 }
 
 // Term returns the terminal type used to create the receiver.
@@ -160,30 +329,42 @@ func newDecorator(term string, ti *terminfo.Terminfo) *Decorator {
 
 		enter: map[item.Type]string{
 			item.BOLD:      ti.Printf(terminfo.EnterBoldMode),
+			item.DIM:       ti.Printf(terminfo.EnterDimMode),
 			item.ITALIC:    ti.Printf(terminfo.EnterItalicsMode),
 			item.UNDERLINE: ti.Printf(terminfo.EnterUnderlineMode),
+			item.REVERSE:   ti.Printf(terminfo.EnterReverseMode),
+			item.BLINK:     ti.Printf(terminfo.EnterBlinkMode),
+			item.STRIKE:    extString(ti, "smxx"),
+			item.INVISIBLE: ti.Printf(terminfo.EnterSecureMode),
+			item.STANDOUT:  ti.Printf(terminfo.EnterStandoutMode),
 		},
 
 		exit: map[item.Type]string{
 			item.BOLD:      ti.Printf(terminfo.ExitAttributeMode),
+			item.DIM:       ti.Printf(terminfo.ExitAttributeMode),
 			item.ITALIC:    ti.Printf(terminfo.ExitItalicsMode),
 			item.UNDERLINE: ti.Printf(terminfo.ExitUnderlineMode),
+			item.REVERSE:   ti.Printf(terminfo.ExitAttributeMode),
+			item.BLINK:     ti.Printf(terminfo.ExitAttributeMode),
+			item.STRIKE:    extString(ti, "rmxx"),
+			item.INVISIBLE: ti.Printf(terminfo.ExitAttributeMode),
+			item.STANDOUT:  ti.Printf(terminfo.ExitStandoutMode),
 		},
 
 		fg: make([]string, len(colors.Names)),
 		bg: make([]string, len(colors.Names)),
-	}
 
-	for n := range colors.Names {
-		d.fg[n] = ti.Printf(terminfo.SetAForeground, n)
-		d.bg[n] = ti.Printf(terminfo.SetABackground, n)
+		hyperlinks: hyperlinkTerm(term),
 	}
 
+	d.setColors(ti, terminfoDepth(ti))
+	d.setUnderline(ti)
+
 	return d
 }
 
 func (d *Decorator) enterCode(itm *item.Item) string {
-	if d == nil || itm == nil {
+	if d == nil || d.plain || itm == nil {
 		return ""
 	}
 
@@ -192,6 +373,12 @@ func (d *Decorator) enterCode(itm *item.Item) string {
 		return d.fgColor(itm.Text)
 	case item.BGCOLOR:
 		return d.bgColor(itm.Text)
+	case item.ULCOLOR:
+		return d.ulColor(itm.Text)
+	case item.UNDERLINE:
+		return d.underline(itm.Text)
+	case item.LINK:
+		return d.linkStart(itm.Text)
 	default:
 		return d.enter[itm.Type]
 	}
@@ -203,15 +390,19 @@ const (
 )
 
 func (d *Decorator) exitCode(itm *item.Item) string {
-	if d == nil || itm == nil {
+	if d == nil || d.plain || itm == nil {
 		return ""
 	}
 
 	switch itm.Type {
 	case item.FGCOLOR:
-		return ansiDefFG
+		return d.defColor(ansiDefFG)
 	case item.BGCOLOR:
-		return ansiDefBG
+		return d.defColor(ansiDefBG)
+	case item.ULCOLOR:
+		return d.defULColor()
+	case item.LINK:
+		return d.linkStart("")
 	default:
 		return d.exit[itm.Type]
 	}
@@ -225,23 +416,44 @@ func (d *Decorator) isAllOff(itm *item.Item) bool {
 	return false
 }
 
-func (d *Decorator) fgColor(s string) string { return lookup(s, d.fg) }
-func (d *Decorator) bgColor(s string) string { return lookup(s, d.bg) }
+const (
+	directFG = "\x1b[38;2;%d;%d;%dm"
+	directBG = "\x1b[48;2;%d;%d;%dm"
+)
+
+func (d *Decorator) fgColor(s string) string {
+	return d.lookup(s, d.fg, func(c color.RGB) string { return fmt.Sprintf(directFG, c.R, c.G, c.B) })
+}
 
-func lookup(clr string, codes []string) string {
+func (d *Decorator) bgColor(s string) string {
+	return d.lookup(s, d.bg, func(c color.RGB) string { return fmt.Sprintf(directBG, c.R, c.G, c.B) })
+}
+
+// lookup returns the terminal code for the given color name, number or
+// direct color specification (e.g. "#ff8800" or "rgb(255,136,0)"). Direct
+// colors are emitted using the given direct function if the terminal supports
+// it; otherwise the nearest color available to the terminal is used instead.
+func (d *Decorator) lookup(clr string, codes []string, direct func(color.RGB) string) string {
 	if n := color.Number(clr); n >= 0 {
 		return codes[n]
 	}
 
-	if n, err := strconv.Atoi(clr); err == nil {
+	if n, err := strconv.Atoi(clr); err == nil && n >= 0 && n < len(codes) {
 		return codes[n]
 	}
 
+	if rgb, err := color.ParseRGB(clr); err == nil {
+		if d.colors >= DirectColors {
+			return direct(rgb)
+		}
+		return codes[color.Nearest(rgb, d.paletteSize())]
+	}
+
 	return fmt.Sprintf("<!color:%s>", clr)
 }
 
 func (d *Decorator) debugf(level int, msg string, args ...any) {
-	if d == nil || d.debug < level {
+	if !d.debugging(level) {
 		return
 	}
 
@@ -251,3 +463,8 @@ func (d *Decorator) debugf(level int, msg string, args ...any) {
 
 	fmt.Fprintf(os.Stderr, msg, args...)
 }
+
+// debugging returns true if the receiver's debug level is at least level.
+func (d *Decorator) debugging(level int) bool {
+	return d != nil && d.debug >= level
+}
@@ -25,3 +25,138 @@ func TestDecorator(t *testing.T) {
 		t.Logf("Got: %q", got)
 	}
 }
+
+func TestDirectColor(t *testing.T) {
+	ti, err := xterm256Terminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newDecorator("xterm-256color", ti)
+
+	cases := []struct {
+		input string
+		depth int
+		want  string
+	}{
+		{"@F{#ff8800}X@f", DirectColors, "\x1b[38;2;255;136;0mX\x1b[39m"},
+		{"@K{#f80}X@k", DirectColors, "\x1b[48;2;255;136;0mX\x1b[49m"},
+		{"@F{rgb(255, 136, 0)}X@f", DirectColors, "\x1b[38;2;255;136;0mX\x1b[39m"},
+		{"@F{#ff8700}X@f", 256, "\x1b[38;5;208mX\x1b[39m"},
+		{"@F{rgb(90,90,90)}X@f", 256, "\x1b[38;5;240mX\x1b[39m"},
+		{"@F{#ff88}X@f", DirectColors, "<!color:#ff88>X\x1b[39m"},
+	}
+
+	for _, tc := range cases {
+		d.setColors(ti, tc.depth)
+		if got, err := d.Format(tc.input); err != nil || got != tc.want {
+			t.Errorf("Format(%q) [depth=%d] == (%q, %v); Wanted (%q, nil)", tc.input, tc.depth, got, err, tc.want)
+		}
+	}
+}
+
+func TestColorDepth(t *testing.T) {
+	ti, err := xterm256Terminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newDecorator("xterm-256color", ti)
+	if got := d.Colors(); got != 256 {
+		t.Errorf("Colors() == %d; Wanted 256", got)
+	}
+
+	cases := []struct {
+		input string
+		depth int
+		want  string
+	}{
+		{"@F{Orchid1}X@f", 256, "\x1b[38;5;213mX\x1b[39m"},
+		{"@F{Magenta1}X@f", 16, "\x1b[95mX\x1b[39m"},
+		{"@F{Magenta1}X@f", 8, "\x1b[35mX\x1b[39m"},
+		{"@F{Orchid1}X@f", 0, "X"},
+		{"@F{BOLD_RED}X@f", 16, "\x1b[91mX\x1b[39m"},
+		{"@F{BOLD_RED}X@f", 8, "\x1b[31mX\x1b[39m"},
+		{"@K{Grey93}X@k", 8, "\x1b[47mX\x1b[49m"},
+		{"@F{#ff8700}X@f", 16, "\x1b[33mX\x1b[39m"},
+		{"@B@F{Red}X@f@b", 0, "\x1b[1mX\x1b(B\x1b[m"},
+	}
+
+	for _, tc := range cases {
+		d.setColors(ti, tc.depth)
+		if got, err := d.Format(tc.input); err != nil || got != tc.want {
+			t.Errorf("Format(%q) [depth=%d] == (%q, %v); Wanted (%q, nil)", tc.input, tc.depth, got, err, tc.want)
+		}
+	}
+}
+
+func TestNesting(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"@F{Red}a@F{Blue}b@fc@f", "\x1b[31ma\x1b[34mb\x1b[31mc\x1b[39m"},
+		{"@K{Grey37}a@K{Blue}b@kc@kd", "\x1b[48;5;59ma\x1b[44mb\x1b[48;5;59mc\x1b[49md"},
+		{"@Ia@Ib@ic@i", "\x1b[3mabc\x1b[23m"},
+		{"@B@F{Red}a@bb@f", "\x1b[1m\x1b[31ma\x1b(B\x1b[m\x1b[31mb\x1b[39m"},
+	}
+
+	for _, tc := range cases {
+		if got, err := d.Format(tc.input); err != nil || got != tc.want {
+			t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", tc.input, got, err, tc.want)
+		}
+	}
+}
+
+func TestNilDecorator(t *testing.T) {
+	var d *Decorator
+
+	input, want := "@B@F{Red}user@f@@@Ihost@i@b", "user@host"
+
+	if got, err := d.Format(input); err != nil || got != want {
+		t.Errorf("(*Decorator)(nil).Format(%q) == (%q, %v); Wanted (%q, nil)", input, got, err, want)
+	}
+
+	if got, err := d.Prompt(Zsh, input); err != nil || got != want {
+		t.Errorf("(*Decorator)(nil).Prompt(Zsh, %q) == (%q, %v); Wanted (%q, nil)", input, got, err, want)
+	}
+
+	if got, want := d.Width("\x1b[1mx%{y"), 4; got != want {
+		t.Errorf("(*Decorator)(nil).Width(...) == %d; Wanted %d", got, want)
+	}
+
+	if got, want := d.Sprintf("@B%s@b", "a@b"), "a@b"; got != want {
+		t.Errorf("(*Decorator)(nil).Sprintf(...) == %q; Wanted %q", got, want)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"@Dx@d", "\x1b[2mx" + xt_sgr0},
+		{"@Rx@r", "\x1b[7mx" + xt_sgr0},
+		{"@Nx@n", "\x1b[5mx" + xt_sgr0},
+		{"@Xx@x", "\x1b[9mx\x1b[29m"},
+		{"@Hx@h", "\x1b[8mx" + xt_sgr0},
+		{"@Sx@s", "\x1b[7mx\x1b[27m"},
+		{"@R@F{Red}x@ry@f", "\x1b[7m\x1b[31mx" + xt_sgr0 + "\x1b[31my" + xt_defFG},
+	}
+
+	for _, tc := range cases {
+		if got, err := d.Format(tc.input); err != nil || got != tc.want {
+			t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", tc.input, got, err, tc.want)
+		}
+	}
+}
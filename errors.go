@@ -0,0 +1,42 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "toolman.org/terminal/decor/internal/series"
+
+// ParseError is the type of error returned when text cannot be parsed as
+// decor notation. It reports the byte Offset, Line and Column at which the
+// problem was detected along with the offending Designator and the error's
+// Kind. Its Snippet method renders the offending line of input with a caret
+// beneath the problem, suitable for display to a user.
+type ParseError = series.ParseError
+
+// ParseErrorKind identifies the type of problem reported by a *ParseError.
+type ParseErrorKind = series.ErrorKind
+
+const (
+	// TrailingSigil indicates text ending with an unescaped '@' or '$'.
+	TrailingSigil = series.TrailingSigil
+
+	// MalformedVariable indicates a '$' not followed by '{' or '$'.
+	MalformedVariable = series.MalformedVariable
+
+	// UnterminatedVariable indicates a variable reference missing its
+	// closing '}'.
+	UnterminatedVariable = series.UnterminatedVariable
+
+	// MissingArgument indicates a designator requiring an argument (such
+	// as @F) found at the end of text.
+	MissingArgument = series.MissingArgument
+
+	// UnterminatedAttribute indicates a designator argument missing its
+	// closing delimiter.
+	UnterminatedAttribute = series.UnterminatedAttribute
+
+	// UnknownAttribute indicates an unrecognized attribute designator
+	// (see Validate and the Strict Option).
+	UnknownAttribute = series.UnknownAttribute
+
+	// UnknownColor indicates a color that cannot be resolved.
+	UnknownColor = series.UnknownColor
+)
@@ -11,28 +11,38 @@ import (
 
 // Format converts the given decor-notated text into a string ready for
 // display on the receiver's associated terminal type, or the empty string
-// and an error if it cannot do so. Note that if text contains any decor
+// and a *ParseError if it cannot do so. Note that if text contains any decor
 // variable references they will be ignored in the resultant output.
 // Create a Template and use its Expand method to resolve decor variables.
 //
+// As with Template's Expand method, attributes turned off as a side effect
+// of stopping another (e.g. ending bold text often resets all attributes)
+// are turned back on and redundant attribute changes are omitted.
+//
 // See the package documentation for more details on decor notation.
 func (d *Decorator) Format(text string) (string, error) {
 	ss := series.New()
 
-	if err := ss.Parse(text); err != nil {
+	if err := d.parse(ss, text); err != nil {
 		return "", err
 	}
 
-	return d.format(ss), nil
+	return d.format(d.optimize(ss)), nil
 }
 
-// Formatf is a wrapper around Format providing a Printf like interface.
+// Formatf is a wrapper around Format providing a Printf like interface. The
+// formatted result is then processed exactly as Format would -- so, any '@'
+// or '$' characters in args are interpreted as decor notation. Use Sprintf to
+// have args inserted literally.
 func (d *Decorator) Formatf(msg string, args ...any) (string, error) {
 	return d.Format(fmt.Sprintf(msg, args...))
 }
 
 func (d *Decorator) format(ss *series.Series) string {
-	var out string
+	var (
+		out   string
+		codes []string
+	)
 
 	for itm := ss.Front(); itm != nil; itm = itm.Next() {
 		d.debugf(1, ">> %s", itm)
@@ -41,17 +51,18 @@ func (d *Decorator) format(ss *series.Series) string {
 		case item.START:
 			code := d.enterCode(itm)
 			d.debugf(1, "++ %q", code)
-			out += code
+			codes = append(codes, code)
 		case item.STOP:
 			code := d.exitCode(itm)
 			d.debugf(1, "-- %q", code)
-			out += code
+			codes = append(codes, code)
 		default:
-			out += itm.Text
+			out += d.wrapCodes(codes) + itm.Text
+			codes = nil
 		}
 	}
 
-	return out
+	return out + d.wrapCodes(codes)
 }
 
 func Strip(text string) (string, error) {
@@ -2,4 +2,9 @@ module toolman.org/terminal/decor
 
 go 1.19
 
-require github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+require (
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e
+	golang.org/x/term v0.15.0
+)
+
+require golang.org/x/sys v0.15.0 // indirect
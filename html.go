@@ -0,0 +1,284 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/colors"
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// HTML renders decor-notated text as HTML instead of terminal codes. Each run
+// of text sharing the same attributes is wrapped in a <span> element styled
+// to match and hyperlinks (@L) become <a> elements. Text is HTML escaped but
+// whitespace is left as-is so output is best placed within a <pre> element.
+// Only hyperlinks using the http, https, mailto or file schemes are rendered
+// as links; text linked to any other URL (e.g. "javascript:...") is not.
+//
+// Colors are rendered using their xterm RGB values. Reverse video (and
+// standout) swaps the foreground and background colors, using the CSS system
+// colors Canvas and CanvasText for those not specified. Blinking text is
+// only distinguished when using CSS classes.
+//
+// The zero value renders attributes as inline styles.
+type HTML struct {
+	// Classes, if true, causes attributes to be rendered as CSS classes
+	// (as defined by the CSS method) instead of inline styles. Direct
+	// colors, having no predefined class, are always rendered inline.
+	Classes bool
+}
+
+// Format returns the HTML rendering of text (see Renderer).
+func (h *HTML) Format(text string) (string, error) {
+	var d *Decorator
+	return d.Render(h, text)
+}
+
+// Expand returns the HTML rendering of t (see Renderer).
+func (h *HTML) Expand(t *Template, values map[string]string) string {
+	return t.Render(h, values)
+}
+
+func (h *HTML) render(ss *series.Series) string {
+	var (
+		out   strings.Builder
+		link  string
+		state = newAttrState()
+	)
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type != item.TEXT && itm.Type != item.ERROR {
+			state.update(itm)
+			continue
+		}
+
+		if itm.Text == "" {
+			continue
+		}
+
+		cur := state.current()
+
+		href := cur[item.LINK]
+		if !safeLink(href) {
+			href = ""
+		}
+
+		if href != link {
+			if link != "" {
+				out.WriteString("</a>")
+			}
+			if link = href; link != "" {
+				fmt.Fprintf(&out, `<a href="%s">`, html.EscapeString(link))
+			}
+		}
+
+		text := html.EscapeString(itm.Text)
+
+		classes, styles := h.attributes(cur)
+		if len(classes) == 0 && len(styles) == 0 {
+			out.WriteString(text)
+			continue
+		}
+
+		out.WriteString("<span")
+		if len(classes) != 0 {
+			fmt.Fprintf(&out, ` class="%s"`, strings.Join(classes, " "))
+		}
+		if len(styles) != 0 {
+			fmt.Fprintf(&out, ` style="%s"`, strings.Join(styles, ";"))
+		}
+		out.WriteString(">" + text + "</span>")
+	}
+
+	if link != "" {
+		out.WriteString("</a>")
+	}
+
+	return out.String()
+}
+
+// safeSchemes lists the URL schemes of hyperlinks rendered as links.
+var safeSchemes = []string{"http", "https", "mailto", "file"}
+
+// safeLink returns true if the hyperlink URL u has one of the schemes listed
+// in safeSchemes (and therefore cannot, e.g., run javascript).
+func safeLink(u string) bool {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+
+	for _, s := range safeSchemes {
+		if strings.EqualFold(pu.Scheme, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// htmlStyles maps simple attributes to their CSS class suffix and inline
+// style. Attributes not listed here are handled separately by attributes.
+var htmlStyles = map[item.Type][2]string{
+	item.BOLD:      {"bold", "font-weight:bold"},
+	item.DIM:       {"dim", "opacity:0.5"},
+	item.ITALIC:    {"italic", "font-style:italic"},
+	item.BLINK:     {"blink", ""},
+	item.INVISIBLE: {"invisible", "visibility:hidden"},
+}
+
+// htmlULStyles maps underline style names to their CSS text-decoration-style.
+var htmlULStyles = map[string]string{
+	"single": "solid",
+	"double": "double",
+	"curly":  "wavy",
+	"dotted": "dotted",
+	"dashed": "dashed",
+}
+
+// attributes returns the CSS classes and inline styles for the attributes
+// in cur.
+func (h *HTML) attributes(cur map[item.Type]string) (classes, styles []string) {
+	add := func(class, style string) {
+		switch {
+		case h.Classes && class != "":
+			classes = append(classes, "decor-"+class)
+		case style != "":
+			styles = append(styles, style)
+		}
+	}
+
+	for t, s := range htmlStyles {
+		if _, ok := cur[t]; ok {
+			add(s[0], s[1])
+		}
+	}
+
+	// Sort for stable output since the above is in map order.
+	sort.Strings(classes)
+	sort.Strings(styles)
+
+	var lines []string
+	if style, ok := cur[item.UNDERLINE]; ok {
+		lines = append(lines, "underline")
+
+		if css := htmlULStyles[style]; css != "" && style != "single" {
+			add("underline-"+style, "text-decoration-style:"+css)
+		} else {
+			add("underline", "")
+		}
+
+		if clr, ok := resolveColor(cur[item.ULCOLOR]); ok {
+			styles = append(styles, "text-decoration-color:"+clr.String())
+		}
+	}
+
+	if _, ok := cur[item.STRIKE]; ok {
+		lines = append(lines, "line-through")
+		add("strike", "")
+	}
+
+	if len(lines) != 0 && !h.Classes {
+		styles = append(styles, "text-decoration-line:"+strings.Join(lines, " "))
+	}
+
+	fg, bg := cur[item.FGCOLOR], cur[item.BGCOLOR]
+	fgc, bgc := "fg", "bg"
+
+	_, rev := cur[item.REVERSE]
+	_, so := cur[item.STANDOUT]
+	if rev || so {
+		fgc, bgc = bgc, fgc
+		add("reverse", "")
+
+		if !h.Classes {
+			if fg == "" {
+				styles = append(styles, "background-color:CanvasText")
+			}
+			if bg == "" {
+				styles = append(styles, "color:Canvas")
+			}
+		}
+	}
+
+	for _, c := range [][2]string{{fgc, fg}, {bgc, bg}} {
+		if c[1] == "" {
+			continue
+		}
+
+		rgb, ok := resolveColor(c[1])
+		if !ok {
+			continue
+		}
+
+		prop := "color:"
+		if c[0] == "bg" {
+			prop = "background-color:"
+		}
+
+		if n, ok := paletteNumber(c[1]); ok && h.Classes {
+			classes = append(classes, fmt.Sprintf("decor-%s-%d", c[0], n))
+		} else {
+			styles = append(styles, prop+rgb.String())
+		}
+	}
+
+	return classes, styles
+}
+
+// paletteNumber returns the palette number for clr if it's a color name or
+// number (as opposed to a direct color).
+func paletteNumber(clr string) (int, bool) {
+	if n := color.Number(clr); n >= 0 {
+		return n, true
+	}
+
+	if n, err := strconv.Atoi(clr); err == nil && n >= 0 && n < len(colors.Values) {
+		return n, true
+	}
+
+	return 0, false
+}
+
+// CSS returns a stylesheet defining the classes used when the receiver's
+// Classes field is true: "decor-bold", "decor-dim", "decor-italic",
+// "decor-underline" (and "decor-underline-curly", etc. for other underline
+// styles), "decor-blink", "decor-reverse", "decor-strike", "decor-invisible"
+// along with "decor-fg-N" and "decor-bg-N" for each palette color number N.
+func (h *HTML) CSS() string {
+	var b strings.Builder
+
+	b.WriteString(".decor-bold { font-weight: bold; }\n")
+	b.WriteString(".decor-dim { opacity: 0.5; }\n")
+	b.WriteString(".decor-italic { font-style: italic; }\n")
+	b.WriteString(".decor-underline { text-decoration-line: underline; }\n")
+
+	for _, style := range []string{"double", "curly", "dotted", "dashed"} {
+		fmt.Fprintf(&b, ".decor-underline-%s { text-decoration-line: underline; text-decoration-style: %s; }\n", style, htmlULStyles[style])
+	}
+
+	b.WriteString(".decor-strike { text-decoration-line: line-through; }\n")
+	b.WriteString("[class*=decor-underline].decor-strike { text-decoration-line: underline line-through; }\n")
+	b.WriteString(".decor-blink { animation: decor-blink 1s step-end infinite; }\n")
+	b.WriteString("@keyframes decor-blink { 50% { opacity: 0; } }\n")
+	b.WriteString(".decor-reverse { color: Canvas; background-color: CanvasText; }\n")
+	b.WriteString(".decor-invisible { visibility: hidden; }\n")
+
+	for n := range colors.Values {
+		fmt.Fprintf(&b, ".decor-fg-%d { color: %s; }\n", n, color.Value(uint8(n)))
+	}
+
+	for n := range colors.Values {
+		fmt.Fprintf(&b, ".decor-bg-%d { background-color: %s; }\n", n, color.Value(uint8(n)))
+	}
+
+	return b.String()
+}
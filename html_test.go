@@ -0,0 +1,106 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	cases := []struct {
+		text    string
+		inline  string
+		classes string
+	}{
+		{
+			"a<b> & @Bbold@b",
+			`a&lt;b&gt; &amp; <span style="font-weight:bold">bold</span>`,
+			`a&lt;b&gt; &amp; <span class="decor-bold">bold</span>`,
+		},
+		{
+			"@F{Red1}a@F{44}b@fc@f",
+			`<span style="color:#ff0000">a</span><span style="color:#00d7d7">b</span><span style="color:#ff0000">c</span>`,
+			`<span class="decor-fg-196">a</span><span class="decor-fg-44">b</span><span class="decor-fg-196">c</span>`,
+		},
+		{
+			"@I@K{#102030}x@k@iy",
+			`<span style="font-style:italic;background-color:#102030">x</span>y`,
+			`<span class="decor-italic" style="background-color:#102030">x</span>y`,
+		},
+		{
+			"@U{curly}@C{Red1}@Xtypo@x@c@u",
+			`<span style="text-decoration-style:wavy;text-decoration-color:#ff0000;text-decoration-line:underline line-through">typo</span>`,
+			`<span class="decor-underline-curly decor-strike" style="text-decoration-color:#ff0000">typo</span>`,
+		},
+		{
+			"@R@F{BLUE}x@f@r",
+			`<span style="color:Canvas;background-color:#0000ee">x</span>`,
+			`<span class="decor-reverse decor-bg-4">x</span>`,
+		},
+		{
+			`@L{https://x.y/?a=1&b=2}see @Bhere@b@l.`,
+			`<a href="https://x.y/?a=1&amp;b=2">see <span style="font-weight:bold">here</span></a>.`,
+			`<a href="https://x.y/?a=1&amp;b=2">see <span class="decor-bold">here</span></a>.`,
+		},
+		{
+			"@L{javascript:alert(1)}x@l @L{MailTo:a@b.c}y@l @L{ javascript:z}z@l",
+			`x <a href="MailTo:a@b.c">y</a> z`,
+			`x <a href="MailTo:a@b.c">y</a> z`,
+		},
+	}
+
+	for _, tc := range cases {
+		if got, err := (&HTML{}).Format(tc.text); err != nil || got != tc.inline {
+			t.Errorf("HTML.Format(%q) == (%q, %v); Wanted (%q, nil)", tc.text, got, err, tc.inline)
+		}
+
+		if got, err := (&HTML{Classes: true}).Format(tc.text); err != nil || got != tc.classes {
+			t.Errorf("HTML{Classes}.Format(%q) == (%q, %v); Wanted (%q, nil)", tc.text, got, err, tc.classes)
+		}
+	}
+}
+
+func TestHTMLExpand(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := d.Template("@F{Red1}[${name}]@f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<span style="color:#ff0000">[</span><span style="color:#0000ee">a&amp;b</span><span style="color:#ff0000">]</span>`
+	if got := (&HTML{}).Expand(tmpl, map[string]string{"name": "@F{BLUE}a&b"}); got != want {
+		t.Errorf("HTML.Expand(...) == %q; Wanted %q", got, want)
+	}
+
+	css := (&HTML{}).CSS()
+	for _, want := range []string{".decor-bold {", ".decor-fg-196 { color: #ff0000; }", ".decor-bg-0 {"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("HTML.CSS() missing %q", want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "@F{Gren3}x@f"
+	want := "x"
+
+	if got, err := d.Render(&HTML{}, text); err != nil || got != want {
+		t.Errorf("Render(%q) == (%q, %v); Wanted (%q, nil)", text, got, err, want)
+	}
+
+	d.apply([]Option{Strict()})
+
+	if got, err := d.Render(&HTML{}, text); err == nil {
+		t.Errorf("Render(%q) == (%q, nil); Wanted UnknownColor error", text, got)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hyperlinkTerms lists the prefixes of terminal types known to support OSC 8
+// hyperlinks.
+var hyperlinkTerms = []string{
+	"alacritty",
+	"contour",
+	"foot",
+	"wezterm",
+	"xterm-ghostty",
+	"xterm-kitty",
+}
+
+// hyperlinkPrograms lists the values of $TERM_PROGRAM for terminals known
+// to support OSC 8 hyperlinks.
+var hyperlinkPrograms = []string{
+	"ghostty",
+	"iTerm.app",
+	"vscode",
+	"WezTerm",
+}
+
+// hyperlinkTerm returns true if the given terminal type is known to support
+// OSC 8 hyperlinks.
+func hyperlinkTerm(term string) bool {
+	for _, t := range hyperlinkTerms {
+		if strings.HasPrefix(term, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hyperlinkEnv returns true if the environment (as provided by getenv)
+// indicates a terminal known to support OSC 8 hyperlinks.
+func hyperlinkEnv(getenv func(string) string) bool {
+	prog := getenv("TERM_PROGRAM")
+	for _, p := range hyperlinkPrograms {
+		if prog == p {
+			return true
+		}
+	}
+
+	// GNOME Terminal (and other VTE based terminals) since VTE 0.50
+	if v, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+
+	// Windows Terminal
+	return getenv("WT_SESSION") != ""
+}
+
+// linkStart returns the OSC 8 sequence that begins a hyperlink to the given
+// URL -- or ends the current hyperlink if url is empty. If the receiver's
+// terminal is not known to support hyperlinks, the empty string is returned.
+// Bytes of url outside the printable ASCII range are percent-encoded so they
+// cannot terminate (or otherwise escape) the sequence.
+func (d *Decorator) linkStart(url string) string {
+	if !d.hyperlinks {
+		return ""
+	}
+
+	return "\x1b]8;;" + escapeURL(url) + "\x1b\\"
+}
+
+// escapeURL returns url with each byte outside of the range 0x20 to 0x7e
+// percent-encoded, as required for the URI of an OSC 8 hyperlink.
+func escapeURL(url string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(url); i++ {
+		if c := url[i]; c < 0x20 || c > 0x7e {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
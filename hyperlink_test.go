@@ -0,0 +1,87 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"testing"
+)
+
+func TestHyperlink(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		start = "\x1b]8;;https://example.com\x1b\\"
+		end   = "\x1b]8;;\x1b\\"
+	)
+
+	cases := []struct {
+		label      string
+		hyperlinks bool
+		input      string
+		want       string
+	}{
+		{"enabled", true, "see @L{https://example.com}here@l.", "see " + start + "here" + end + "."},
+		{"disabled", false, "see @L{https://example.com}here@l.", "see here."},
+		{"across-sgr0", true, "@L<https://example.com>@Ba@bb@l", start + "\x1b[1ma" + xt_sgr0 + "b" + end},
+		{"escaped", true, "@L{http://x\x1b]0;pwned\a/ü}a@l", "\x1b]8;;http://x%1B]0;pwned%07/%C3%BC\x1b\\a" + end},
+		{"nested", true, "@L(https://example.com)a@L(https://b.example)b@lc@l", start + "a\x1b]8;;https://b.example\x1b\\b" + start + "c" + end},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			d.apply([]Option{Hyperlinks(tc.hyperlinks)})
+			if got, err := d.Format(tc.input); err != nil || got != tc.want {
+				t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", tc.input, got, err, tc.want)
+			}
+		})
+	}
+
+	d.apply([]Option{Hyperlinks(true)})
+
+	// A variable's attributes are stopped upon its expansion, which must
+	// restore the link in effect beforehand even if doing so emits 'sgr0'.
+	tmpl, err := d.Template("@L{a}x${v}y@l")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{"v": "@L{b}@Dz"}
+	if got, want := tmpl.Expand(values), "\x1b]8;;a\x1b\\x\x1b]8;;b\x1b\\\x1b[2mz"+xt_sgr0+"\x1b]8;;a\x1b\\y"+end; got != want {
+		t.Errorf("Template(%q).Expand(%v) == %q; Wanted %q", "@L{a}x${v}y@l", values, got, want)
+	}
+
+	input, want := "see @L{https://example.com}here@l.", "see here."
+	if got, err := Strip(input); err != nil || got != want {
+		t.Errorf("Strip(%q) == (%q, %v); Wanted (%q, nil)", input, got, err, want)
+	}
+}
+
+func TestHyperlinkDetection(t *testing.T) {
+	if !hyperlinkTerm("xterm-kitty") || hyperlinkTerm("xterm-256color") {
+		t.Errorf("hyperlinkTerm mismatch")
+	}
+
+	env := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+
+	cases := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{map[string]string{"VTE_VERSION": "6003"}, true},
+		{map[string]string{"VTE_VERSION": "4205"}, false},
+		{map[string]string{"WT_SESSION": "abc"}, true},
+		{map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, false},
+	}
+
+	for _, tc := range cases {
+		if got := hyperlinkEnv(env(tc.env)); got != tc.want {
+			t.Errorf("hyperlinkEnv(%v) == %t; Wanted %t", tc.env, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package colors
+
+// Values holds the red, green and blue components for each of the 256 xterm
+// color numbers, as indexed by color number.
+var Values = buildValues()
+
+var basic = [16][3]uint8{
+	{0x00, 0x00, 0x00},
+	{0xcd, 0x00, 0x00},
+	{0x00, 0xcd, 0x00},
+	{0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee},
+	{0xcd, 0x00, 0xcd},
+	{0x00, 0xcd, 0xcd},
+	{0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f},
+	{0xff, 0x00, 0x00},
+	{0x00, 0xff, 0x00},
+	{0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff},
+	{0xff, 0x00, 0xff},
+	{0x00, 0xff, 0xff},
+	{0xff, 0xff, 0xff},
+}
+
+func buildValues() [][3]uint8 {
+	vals := make([][3]uint8, 0, 256)
+	vals = append(vals, basic[:]...)
+
+	// Colors 16 through 231 make up a 6x6x6 color cube...
+	levels := []uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+	for _, r := range levels {
+		for _, g := range levels {
+			for _, b := range levels {
+				vals = append(vals, [3]uint8{r, g, b})
+			}
+		}
+	}
+
+	// ...followed by a 24 step grayscale ramp.
+	for i := 0; i < 24; i++ {
+		v := uint8(8 + 10*i)
+		vals = append(vals, [3]uint8{v, v, v})
+	}
+
+	return vals
+}
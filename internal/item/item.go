@@ -27,24 +27,56 @@ func AttrItem(c byte) *Item {
 	switch c {
 	case 'B':
 		return StartItem(BOLD)
+	case 'C':
+		return StartItem(ULCOLOR)
+	case 'D':
+		return StartItem(DIM)
 	case 'F':
 		return StartItem(FGCOLOR)
+	case 'H':
+		return StartItem(INVISIBLE)
 	case 'I':
 		return StartItem(ITALIC)
 	case 'K':
 		return StartItem(BGCOLOR)
+	case 'L':
+		return StartItem(LINK)
+	case 'N':
+		return StartItem(BLINK)
+	case 'R':
+		return StartItem(REVERSE)
+	case 'S':
+		return StartItem(STANDOUT)
 	case 'U':
 		return StartItem(UNDERLINE)
+	case 'X':
+		return StartItem(STRIKE)
 	case 'b':
 		return StopItem(BOLD)
+	case 'c':
+		return StopItem(ULCOLOR)
+	case 'd':
+		return StopItem(DIM)
 	case 'f':
 		return StopItem(FGCOLOR)
+	case 'h':
+		return StopItem(INVISIBLE)
 	case 'i':
 		return StopItem(ITALIC)
 	case 'k':
 		return StopItem(BGCOLOR)
+	case 'l':
+		return StopItem(LINK)
+	case 'n':
+		return StopItem(BLINK)
+	case 'r':
+		return StopItem(REVERSE)
+	case 's':
+		return StopItem(STANDOUT)
 	case 'u':
 		return StopItem(UNDERLINE)
+	case 'x':
+		return StopItem(STRIKE)
 	default:
 		return &Item{ID: nextID()}
 	}
@@ -66,6 +98,18 @@ func BGColorItem(color string) *Item {
 	return itm
 }
 
+// SplitVarRef splits ref, the Text of a VAR Item, into the referenced
+// variable's name along with the operator (":-", ":+" or ":?") and word of
+// a conditional reference such as "${name:-default}". For a simple
+// reference, op and word are empty.
+func SplitVarRef(ref string) (name, op, word string) {
+	if j := strings.IndexByte(ref, ':'); j != -1 && j+1 < len(ref) && strings.IndexByte("-+?", ref[j+1]) != -1 {
+		return ref[:j], ref[j : j+2], ref[j+2:]
+	}
+
+	return ref, "", ""
+}
+
 func SaveItem() *Item    { return StartItem(SAVE) }
 func RestoreItem() *Item { return StopItem(SAVE) }
 
@@ -0,0 +1,81 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package item
+
+import "strings"
+
+// designators maps each attribute Type to its (start) designator character.
+var designators = map[Type]byte{
+	BOLD:      'B',
+	ULCOLOR:   'C',
+	DIM:       'D',
+	FGCOLOR:   'F',
+	INVISIBLE: 'H',
+	ITALIC:    'I',
+	BGCOLOR:   'K',
+	LINK:      'L',
+	BLINK:     'N',
+	REVERSE:   'R',
+	STANDOUT:  'S',
+	UNDERLINE: 'U',
+	STRIKE:    'X',
+}
+
+// argDelims lists the delimiter pairs that may surround an attribute
+// argument, in order of preference.
+var argDelims = []string{"{}", "()", "[]", "<>", "||", "++", "::"}
+
+// UnderlineStyles lists the names of the underline styles that may follow
+// an underline designator, in order of their extended "Smulx" terminfo
+// parameter (starting from 1).
+var UnderlineStyles = []string{"single", "double", "curly", "dotted", "dashed"}
+
+// IsUnderlineStyle returns true if name is one of UnderlineStyles.
+func IsUnderlineStyle(name string) bool {
+	for _, s := range UnderlineStyles {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+var escaper = strings.NewReplacer("@", "@@", "$", "$$")
+
+// Notation returns the decor notation that would parse as the receiver. The
+// empty string is returned for Items having no such notation (e.g. SAVE).
+func (i *Item) Notation() string {
+	if i == nil {
+		return ""
+	}
+
+	switch i.Type {
+	case TEXT:
+		return escaper.Replace(i.Text)
+	case VAR:
+		return "${" + i.Text + "}"
+	}
+
+	c, ok := designators[i.Type]
+	if !ok {
+		return ""
+	}
+
+	if i.Action == STOP {
+		return "@" + strings.ToLower(string(c))
+	}
+
+	if i.Text == "" {
+		return "@" + string(c)
+	}
+
+	for _, d := range argDelims {
+		if !strings.ContainsAny(i.Text, d) {
+			return "@" + string(c) + d[:1] + i.Text + d[1:]
+		}
+	}
+
+	// Any delimiter will do if the argument contains all of the above
+	// (since it cannot be represented anyway).
+	return "@" + string(c) + "{" + i.Text + "}"
+}
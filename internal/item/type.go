@@ -16,6 +16,14 @@ const (
 	ITALIC
 	FGCOLOR
 	BGCOLOR
+	DIM
+	REVERSE
+	BLINK
+	STRIKE
+	INVISIBLE
+	STANDOUT
+	ULCOLOR
+	LINK
 )
 
 type Type int
@@ -40,6 +48,22 @@ func (t Type) String() string {
 		return "FGCOLOR"
 	case BGCOLOR:
 		return "BGCOLOR"
+	case DIM:
+		return "DIM"
+	case REVERSE:
+		return "REVERSE"
+	case BLINK:
+		return "BLINK"
+	case STRIKE:
+		return "STRIKE"
+	case INVISIBLE:
+		return "INVISIBLE"
+	case STANDOUT:
+		return "STANDOUT"
+	case ULCOLOR:
+		return "ULCOLOR"
+	case LINK:
+		return "LINK"
 	default:
 		return "<UNKNOWN>"
 	}
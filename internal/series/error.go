@@ -0,0 +1,151 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package series
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrorKind identifies the type of problem reported by a ParseError.
+type ErrorKind int
+
+const (
+	// TrailingSigil indicates input ending with an unescaped '@' or '$'.
+	TrailingSigil ErrorKind = iota + 1
+
+	// MalformedVariable indicates a '$' sigil not followed by '{' or '$'.
+	MalformedVariable
+
+	// UnterminatedVariable indicates a variable reference missing its
+	// closing '}'.
+	UnterminatedVariable
+
+	// MissingArgument indicates an attribute designator requiring an
+	// argument (e.g. a color) found at the end of input.
+	MissingArgument
+
+	// UnterminatedAttribute indicates an attribute argument missing its
+	// closing delimiter.
+	UnterminatedAttribute
+
+	// UnknownAttribute indicates an unrecognized attribute designator.
+	UnknownAttribute
+
+	// UnknownColor indicates a color argument that cannot be resolved.
+	UnknownColor
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case TrailingSigil:
+		return "trailing sigil"
+	case MalformedVariable:
+		return "malformed variable reference"
+	case UnterminatedVariable:
+		return "unterminated variable reference"
+	case MissingArgument:
+		return "missing argument"
+	case UnterminatedAttribute:
+		return "unterminated attribute"
+	case UnknownAttribute:
+		return "unknown attribute"
+	case UnknownColor:
+		return "unknown color"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// ParseError describes a failure to parse decor notation, including where
+// in the input the failure was detected.
+type ParseError struct {
+	// Input is the full text being parsed.
+	Input string
+
+	// Offset is the byte offset into Input of the offending designator.
+	Offset int
+
+	// Line and Column are the 1-based line number and (rune) column of
+	// the offending designator.
+	Line   int
+	Column int
+
+	// Designator is the offending designator or variable reference prefix
+	// (e.g. "@F" or "${").
+	Designator string
+
+	// Argument is the offending designator argument, if any.
+	Argument string
+
+	// Kind identifies the type of problem encountered.
+	Kind ErrorKind
+
+	// Suggestion, if not empty, is a likely replacement for Argument.
+	Suggestion string
+}
+
+func newParseError(input string, offset int, designator string, kind ErrorKind) *ParseError {
+	line := 1 + strings.Count(input[:offset], "\n")
+	bol := strings.LastIndexByte(input[:offset], '\n') + 1
+
+	return &ParseError{
+		Input:      input,
+		Offset:     offset,
+		Line:       line,
+		Column:     1 + utf8.RuneCountInString(input[bol:offset]),
+		Designator: designator,
+		Kind:       kind,
+	}
+}
+
+func (e *ParseError) Error() string {
+	var msg string
+
+	switch e.Kind {
+	case TrailingSigil:
+		msg = fmt.Sprintf("sigil %q not allowed at end of string", e.Designator)
+	case MissingArgument:
+		msg = fmt.Sprintf("missing argument for attribute %s", e.Designator)
+	case UnterminatedAttribute, UnknownAttribute:
+		msg = fmt.Sprintf("%s %s", e.Kind, e.Designator)
+	case UnknownColor:
+		msg = fmt.Sprintf("%s %q for %s", e.Kind, e.Argument, e.Designator)
+	default:
+		msg = e.Kind.String()
+	}
+
+	if e.Line > 1 {
+		msg = fmt.Sprintf("%s at line %d, col %d", msg, e.Line, e.Column)
+	} else {
+		msg = fmt.Sprintf("%s at pos %d", msg, e.Offset)
+	}
+
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", e.Suggestion)
+	}
+
+	return msg
+}
+
+// Snippet returns the line of Input containing the offending designator
+// followed by a second line with a caret ('^') positioned beneath it.
+func (e *ParseError) Snippet() string {
+	bol := strings.LastIndexByte(e.Input[:e.Offset], '\n') + 1
+
+	line := e.Input[bol:]
+	if i := strings.IndexByte(line, '\n'); i != -1 {
+		line = line[:i]
+	}
+
+	// Preserve tabs so the caret lines up regardless of tab width.
+	pad := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, e.Input[bol:e.Offset])
+
+	return line + "\n" + pad + "^"
+}
@@ -3,31 +3,68 @@
 package series
 
 import (
-	"fmt"
 	"strings"
 
 	"toolman.org/terminal/decor/internal/item"
 )
 
+// Parse parses the decor-notated input and appends the resulting items to
+// the receiver. If input cannot be parsed, a *ParseError is returned.
 func (s *Series) Parse(input string) error {
-	var bufstr string
+	return s.ParseStrict(input, nil)
+}
+
+// A Checker examines each attribute item produced by ParseStrict, returning
+// a non-zero ErrorKind if the item is not acceptable. A non-empty suggestion
+// may also be returned to offer a likely replacement for the item's Text.
+type Checker func(itm *item.Item) (kind ErrorKind, suggestion string)
+
+// ParseStrict is like Parse but also submits each attribute item to check
+// (if not nil), returning a *ParseError for the first item it rejects.
+func (s *Series) ParseStrict(input string, check Checker) error {
+	if errs := s.parse(input, check, false); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Validate parses input using check as with ParseStrict but, instead of
+// stopping at the first rejected item, a *ParseError is returned for each
+// of them. Since malformed input cannot be parsed further, a syntax error
+// is always the last error returned.
+func Validate(input string, check Checker) []error {
+	var errs []error
+
+	for _, pe := range New().parse(input, check, true) {
+		errs = append(errs, pe)
+	}
+
+	return errs
+}
+
+func (s *Series) parse(input string, check Checker, all bool) []*ParseError {
+	var (
+		bufstr string
+		errs   []*ParseError
+	)
 
-	for input != "" {
-		i := strings.IndexAny(input, "@$")
+	for pos := 0; pos < len(input); {
+		i := strings.IndexAny(input[pos:], "@$")
 		if i == -1 {
-			bufstr += input
+			bufstr += input[pos:]
 			break
 		}
 
+		i += pos
 		sigil := input[i]
 
 		if i == len(input)-1 {
-			return fmt.Errorf("sigil %q not allowed at end of string", sigil)
+			return append(errs, newParseError(input, i, string(sigil), TrailingSigil))
 		}
 
-		bufstr += input[:i]
+		bufstr += input[pos:i]
 		c := input[i+1]
-		input = input[i+2:]
+		pos = i + 2
 
 		if c == sigil {
 			bufstr += string(sigil)
@@ -41,56 +78,234 @@ func (s *Series) Parse(input string) error {
 
 		if sigil == '$' {
 			if c != '{' {
-				return fmt.Errorf("malformed variable reference at pos %d", i)
+				return append(errs, newParseError(input, i, input[i:i+2], MalformedVariable))
 			}
 
-			j := strings.IndexByte(input, '}')
+			j := varEnd(input[pos:])
 			if j == -1 {
-				return fmt.Errorf("unterminated variable reference at pod %d", i)
+				return append(errs, newParseError(input, i, input[i:i+2], UnterminatedVariable))
 			}
 
-			s.Append(item.VarItem(input[:j]))
-			input = input[j+1:]
+			ref := input[pos : pos+j]
+
+			// The default (or alternate) value of a conditional
+			// reference is itself decor notation.
+			if _, op, word := item.SplitVarRef(ref); op == ":-" || op == ":+" {
+				base := pos + len(ref) - len(word)
+				for _, pe := range New().parse(word, check, all) {
+					npe := newParseError(input, base+pe.Offset, pe.Designator, pe.Kind)
+					npe.Argument = pe.Argument
+					npe.Suggestion = pe.Suggestion
+
+					if errs = append(errs, npe); !all || pe.Kind < UnknownAttribute {
+						return errs
+					}
+				}
+			}
+
+			s.Append(item.VarItem(ref))
+			pos += j + 1
 			continue
 		}
 
 		sgmt := item.AttrItem(c)
 
-		if c != 'F' && c != 'K' {
-			s.Append(sgmt)
+		if takesArg(c, input[pos:]) {
+			if pos == len(input) {
+				return append(errs, newParseError(input, i, input[i:i+2], MissingArgument))
+			}
+
+			cc := closer(input[pos])
+			pos++
+
+			j := strings.IndexByte(input[pos:], cc)
+			if j == -1 {
+				return append(errs, newParseError(input, i, input[i:i+2], UnterminatedAttribute))
+			}
+
+			sgmt.Text = input[pos : pos+j]
+			pos += j + 1
+		}
+
+		if check != nil {
+			if kind, hint := check(sgmt); kind != 0 {
+				pe := newParseError(input, i, input[i:i+2], kind)
+				pe.Argument = sgmt.Text
+				pe.Suggestion = hint
+
+				if errs = append(errs, pe); !all {
+					return errs
+				}
+			}
+		}
+
+		s.Append(sgmt)
+	}
+
+	if bufstr != "" {
+		s.Append(item.TextItem(bufstr))
+	}
+
+	return errs
+}
+
+// Complete returns the length of the longest prefix of input that does not
+// end with a partial designator or variable reference (i.e. one that could
+// be completed by additional input). If input contains no such partial
+// reference, len(input) is returned. Note that malformed input that cannot
+// be completed is left for Parse to report.
+func Complete(input string) int {
+	for i := 0; i < len(input); i++ {
+		sigil := input[i]
+		if sigil != '@' && sigil != '$' {
 			continue
 		}
 
-		var cc byte
-		switch input[0] {
-		case '{':
-			cc = '}'
-		case '(':
-			cc = ')'
-		case '[':
-			cc = ']'
-		case '<':
-			cc = '>'
+		if i == len(input)-1 {
+			return i
+		}
+
+		c := input[i+1]
+		rest := input[i+2:]
+
+		switch {
+		case c == sigil:
+			i++
+
+		case sigil == '$':
+			if c != '{' {
+				i++
+				continue
+			}
+
+			j := varEnd(rest)
+			if j == -1 {
+				return i
+			}
+			i += j + 2
+
+		case c == 'U' && partialStyle(rest):
+			// A style may yet follow (or be completed)
+			return i
+
+		case takesArg(c, rest):
+			if rest == "" {
+				return i
+			}
+
+			j := strings.IndexByte(rest[1:], closer(rest[0]))
+			if j == -1 {
+				return i
+			}
+			i += j + 3
+
 		default:
-			cc = input[0]
+			i++
 		}
+	}
 
-		input = input[1:]
+	return len(input)
+}
+
+// varEnd returns the index of the '}' closing the variable reference whose
+// body begins input, or -1 if there is none. The body may itself contain
+// variable references and attribute designators (e.g. the default value in
+// "${name:-@F{Red}${other}@f}").
+func varEnd(input string) int {
+	for i := 0; i < len(input); i++ {
+		sigil := input[i]
+
+		switch {
+		case sigil == '}':
+			return i
+
+		case sigil != '@' && sigil != '$':
+			continue
 
-		j := strings.IndexByte(input, cc)
-		if j == -1 {
-			return fmt.Errorf("unterminated attribute %q at pos %d", c, i)
+		case i == len(input)-1:
+			return -1
 		}
 
-		sgmt.Text = input[:j]
-		s.Append(sgmt)
+		c := input[i+1]
+		rest := input[i+2:]
+
+		switch {
+		case c == sigil:
+			i++
 
-		input = input[j+1:]
+		case sigil == '$' && c == '{':
+			j := varEnd(rest)
+			if j == -1 {
+				return -1
+			}
+			i += j + 2
+
+		case sigil == '@' && takesArg(c, rest) && rest != "":
+			j := strings.IndexByte(rest[1:], closer(rest[0]))
+			if j == -1 {
+				return -1
+			}
+			i += j + 3
+
+		default:
+			i++
+		}
 	}
 
-	if bufstr != "" {
-		s.Append(item.TextItem(bufstr))
+	return -1
+}
+
+// takesArg returns true if the attribute designated by c is followed by an
+// argument, given the remaining input following the designator.
+func takesArg(c byte, rest string) bool {
+	switch c {
+	case 'F', 'K', 'C', 'L':
+		// These attributes require an argument
+		return true
+	case 'U':
+		// Underline has an optional (brace delimited) style name; any
+		// other text is left as is.
+		j := strings.IndexByte(rest, '}')
+		return strings.HasPrefix(rest, "{") && j != -1 && item.IsUnderlineStyle(rest[1:j])
+	default:
+		return false
+	}
+}
+
+// partialStyle returns true if rest could be extended to begin with a brace
+// delimited underline style name.
+func partialStyle(rest string) bool {
+	if rest == "" {
+		return true
 	}
 
-	return nil
+	if rest[0] != '{' || strings.IndexByte(rest, '}') != -1 {
+		return false
+	}
+
+	for _, s := range item.UnderlineStyles {
+		if strings.HasPrefix(s, rest[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closer returns the closing delimiter for an argument beginning with the
+// given opening delimiter. Brace-like characters are closed by their
+// matching counterpart while any other character closes itself.
+func closer(open byte) byte {
+	switch open {
+	case '{':
+		return '}'
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	case '<':
+		return '>'
+	default:
+		return open
+	}
 }
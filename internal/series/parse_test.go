@@ -32,3 +32,187 @@ func TestSeries(t *testing.T) {
 		t.Logf("OK: s.Parse(%q) -> >>%s<<", input, s)
 	}
 }
+
+func TestTopmost(t *testing.T) {
+	s := Build(
+		item.FGColorItem("Red"),
+		item.StartItem(item.BOLD),
+		item.FGColorItem("Blue"),
+		item.StartItem(item.ITALIC),
+		item.StartItem(item.BOLD),
+	)
+
+	want := Build(
+		item.FGColorItem("Blue"),
+		item.StartItem(item.ITALIC),
+		item.StartItem(item.BOLD),
+	)
+
+	if got := s.Topmost(); !got.Equal(want) {
+		t.Errorf("s.Topmost() -> >>%s<< Wanted >>%s<<", got, want)
+	}
+}
+
+func TestParseUnderline(t *testing.T) {
+	s := New()
+
+	curly := item.AttrItem('U')
+	curly.Text = "curly"
+
+	want := Build(
+		curly,
+		item.TextItem("a"),
+		item.AttrItem('u'),
+		item.AttrItem('U'),
+		item.TextItem("[b]"),
+		item.AttrItem('u'),
+		item.AttrItem('U'),
+		item.TextItem("{note}"),
+		item.AttrItem('u'),
+	)
+
+	input := "@U{curly}a@u@U[b]@u@U{note}@u"
+	if err := s.Parse(input); err != nil {
+		t.Errorf("s.Parse(%q) error: %v", input, err)
+	} else if !s.Equal(want) {
+		t.Errorf("s.Parse(%q) -> >>%s<< Wanted >>%s<<", input, s, want)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	cases := []struct {
+		input string
+		want  int
+	}{
+		{"abc", 3},
+		{"abc@", 3},
+		{"abc$", 3},
+		{"abc@@", 5},
+		{"abc@B", 5},
+		{"abc@U", 3},
+		{"abc@U{cur", 3},
+		{"abc@U{curly}", 12},
+		{"abc@U{note", 10},
+		{"abc@U{note}", 11},
+		{"abc@Ux", 6},
+		{"abc@F", 3},
+		{"abc@F{Gre", 3},
+		{"abc@F{Green}x", 13},
+		{"abc@F+Green+", 12},
+		{"abc${Na", 3},
+		{"abc${Name}@f", 12},
+		{"a@F{Red}b@L{https://exa", 9},
+		{"abc${A:-${B}", 3},
+		{"abc${A:-@F{Red}x@f}y", 20},
+		{"abc${A:-@F{Re", 3},
+	}
+
+	for _, tc := range cases {
+		if got := Complete(tc.input); got != tc.want {
+			t.Errorf("Complete(%q) == %d; Wanted %d", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		input   string
+		kind    ErrorKind
+		offset  int
+		line    int
+		column  int
+		desig   string
+		msg     string
+		snippet string
+	}{
+		{"abc@", TrailingSigil, 3, 1, 4, "@", "sigil \"@\" not allowed at end of string at pos 3", "abc@\n   ^"},
+		{"@Bx$y", MalformedVariable, 3, 1, 4, "$y", "malformed variable reference at pos 3", "@Bx$y\n   ^"},
+		{"a${b", UnterminatedVariable, 1, 1, 2, "${", "unterminated variable reference at pos 1", "a${b\n ^"},
+		{"xyz@F", MissingArgument, 3, 1, 4, "@F", "missing argument for attribute @F at pos 3", "xyz@F\n   ^"},
+		{"one\n\ttwo @K{Red", UnterminatedAttribute, 9, 2, 6, "@K", "unterminated attribute @K at line 2, col 6", "\ttwo @K{Red\n\t    ^"},
+		{"ünï@C", MissingArgument, 5, 1, 4, "@C", "missing argument for attribute @C at pos 5", "ünï@C\n   ^"},
+	}
+
+	for _, tc := range cases {
+		err := New().Parse(tc.input)
+
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Parse(%q) == %v; Wanted *ParseError", tc.input, err)
+			continue
+		}
+
+		got := [5]any{pe.Kind, pe.Offset, pe.Line, pe.Column, pe.Designator}
+		want := [5]any{tc.kind, tc.offset, tc.line, tc.column, tc.desig}
+		if got != want {
+			t.Errorf("Parse(%q) == %v; Wanted %v", tc.input, got, want)
+		}
+
+		if msg := pe.Error(); msg != tc.msg {
+			t.Errorf("Parse(%q).Error() == %q; Wanted %q", tc.input, msg, tc.msg)
+		}
+
+		if snip := pe.Snippet(); snip != tc.snippet {
+			t.Errorf("Parse(%q).Snippet() == %q; Wanted %q", tc.input, snip, tc.snippet)
+		}
+	}
+}
+
+func TestNotation(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"@F(Grey37)[${Glyph}:@I${Key}@i]@f", "@F{Grey37}[${Glyph}:@I${Key}@i]@f"},
+		{"a@@b$$c @U{curly}x@u@L<https://x.y/{a}>y@l", "a@@b$$c @U{curly}x@u@L(https://x.y/{a})y@l"},
+	}
+
+	for _, tc := range cases {
+		s := New()
+		if err := s.Parse(tc.input); err != nil {
+			t.Errorf("s.Parse(%q) error: %v", tc.input, err)
+		} else if got := s.Notation(); got != tc.want {
+			t.Errorf("s.Parse(%q).Notation() == %q; Wanted %q", tc.input, got, tc.want)
+		}
+	}
+
+	s := Build(item.AttrItem('U'), item.TextItem("{curly}"), item.AttrItem('u'))
+	if got, want := s.Notation(), "@U{single}{curly}@u"; got != want {
+		t.Errorf("s.Notation() == %q; Wanted %q", got, want)
+	}
+
+	s = Build(item.AttrItem('U'), item.TextItem("{x}"), item.AttrItem('u'))
+	if got, want := s.Notation(), "@U{x}@u"; got != want {
+		t.Errorf("s.Notation() == %q; Wanted %q", got, want)
+	}
+}
+
+func TestParseConditionalVar(t *testing.T) {
+	s := New()
+
+	want := Build(
+		item.TextItem("["),
+		item.VarItem("A:-@F(Red})${B:+x}@f"),
+		item.TextItem("]"),
+		item.VarItem("C"),
+	)
+
+	input := "[${A:-@F(Red})${B:+x}@f}]${C}"
+	if err := s.Parse(input); err != nil {
+		t.Errorf("s.Parse(%q) error: %v", input, err)
+	} else if !s.Equal(want) {
+		t.Errorf("s.Parse(%q) -> >>%s<< Wanted >>%s<<", input, s, want)
+	}
+
+	for ref, want := range map[string][3]string{
+		"name":        {"name", "", ""},
+		"name:-a:b":   {"name", ":-", "a:b"},
+		"name:+":      {"name", ":+", ""},
+		"name:?oops!": {"name", ":?", "oops!"},
+		"name:=x":     {"name:=x", "", ""},
+	} {
+		if n, op, w := item.SplitVarRef(ref); [3]string{n, op, w} != want {
+			t.Errorf("SplitVarRef(%q) == (%q, %q, %q); Wanted %q", ref, n, op, w, want)
+		}
+	}
+}
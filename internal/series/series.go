@@ -115,6 +115,23 @@ func (s *Series) AppendList(other *Series) *Series {
 	return s
 }
 
+// After returns a new Series holding copies of each Item following itm in
+// the receiver's list. If itm is nil, a copy of the entire list is returned.
+func (s *Series) After(itm *item.Item) *Series {
+	ns := New()
+
+	it := s.Front()
+	if itm != nil {
+		it = itm.Next()
+	}
+
+	for ; it != nil; it = it.Next() {
+		ns.Append(it.Detach())
+	}
+
+	return ns
+}
+
 func (s *Series) Clone() *Series {
 	if s.Len() == 0 {
 		return nil
@@ -162,9 +179,19 @@ func (s *Series) RemoveLast(itype item.Type) *item.Item {
 		return nil
 	}
 
-	for it := s.Back(); s != nil; it = it.Prev() {
+	if it := s.Last(itype); it != nil {
+		s.clist.Remove(it.Element())
+		return it
+	}
+
+	return nil
+}
+
+// Last returns the final Item in the receiver's list having the given Type
+// or nil if no such Item exists.
+func (s *Series) Last(itype item.Type) *item.Item {
+	for it := s.Back(); it != nil; it = it.Prev() {
 		if it.Type == itype {
-			s.clist.Remove(it.Element())
 			return it
 		}
 	}
@@ -172,6 +199,25 @@ func (s *Series) RemoveLast(itype item.Type) *item.Item {
 	return nil
 }
 
+// Topmost returns a new Series holding copies of only the final Item of each
+// Type found in the receiver, retaining their original order. For example,
+//
+//	Before......: [A1 B1 A2 C1 B2]
+//	After.......: [A2 C1 B2]
+func (s *Series) Topmost() *Series {
+	var items []*item.Item
+	seen := make(map[item.Type]bool)
+
+	for it := s.Back(); it != nil; it = it.Prev() {
+		if !seen[it.Type] {
+			seen[it.Type] = true
+			items = append([]*item.Item{it}, items...)
+		}
+	}
+
+	return Build(items...)
+}
+
 // RemoveBack removes the element at the back of the reciever's list
 // and returns it. If this list is empty a nil pointer is returned.
 func (s *Series) RemoveBack() *item.Item {
@@ -225,3 +271,23 @@ func (s *Series) Remove(itm *item.Item) *item.Item {
 
 	return nil
 }
+
+// Notation returns the decor notation that would parse as the receiver.
+func (s *Series) Notation() string {
+	var b strings.Builder
+
+	for itm := s.Front(); itm != nil; itm = itm.Next() {
+		n := itm.Notation()
+
+		// A plain underline immediately followed by a brace delimited
+		// style name would instead be parsed as an underline style so an
+		// explicit (and equivalent) "single" style is used instead.
+		if n == "@U" && takesArg('U', itm.Next().Notation()) {
+			n = "@U{single}"
+		}
+
+		b.WriteString(n)
+	}
+
+	return b.String()
+}
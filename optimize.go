@@ -7,82 +7,167 @@ import (
 	"toolman.org/terminal/decor/internal/series"
 )
 
+// optimize returns a new Series derived from input where each attribute STOP
+// restores whatever was in effect before its corresponding START, attributes
+// turned off as a side effect of 'sgr0' are turned back on, SAVE and RESTORE
+// items are replaced with whatever changes are needed to return to the saved
+// state, and redundant or unnecessary attribute changes are removed.
 func (d *Decorator) optimize(input *series.Series) *series.Series {
-	output := series.New()
-	active := series.New()
+	o := d.optimizer()
+	o.add(input)
+	return o.output
+}
+
+// optimizer holds the state needed to optimize a Series. This state is
+// retained across calls to its add method so that a stream of Series may
+// be optimized in pieces (see Writer).
+type optimizer struct {
+	*Decorator
+	output        *series.Series
+	active        *series.Series
+	restorePoints seriesStack
+}
 
-	d.debugf(1, "optimizing %d items: %v", input.Len(), input.ItemIDs())
-	if d.debug > 1 {
-		d.debugf(2, "    %s", input.String())
+func (d *Decorator) optimizer() *optimizer {
+	return &optimizer{
+		Decorator: d,
+		output:    series.New(),
+		active:    series.New(),
 	}
+}
 
-	var restorePoints seriesStack
+// add appends the optimized form of input to the receiver's output.
+func (o *optimizer) add(input *series.Series) {
+	o.debugf(1, "optimizing %d items: %v", input.Len(), input.ItemIDs())
+	if o.debugging(2) {
+		o.debugf(2, "    %s", input.String())
+	}
 
 	for itm := input.Front(); itm != nil; itm = itm.Next() {
-		if d.debug > 1 {
-			d.debugf(2, "ITEM: %s", itm)
-			d.debugf(3, "     Input: %v", input.ItemIDs())
-			d.debugf(3, "    Output: %v", output.ItemIDs())
-			d.debugf(3, "    Active: %v", active.ItemIDs())
+		if o.debugging(2) {
+			o.debugf(2, "ITEM: %s", itm)
+			o.debugf(3, "    Output: %v", o.output.ItemIDs())
+			o.debugf(3, "    Active: %v", o.active.ItemIDs())
 		}
 
 		switch itm.Action {
 		case item.START:
 			if itm.Type == item.SAVE {
-				d.debugf(2, "    Saving Active: %v", active.ItemIDs())
-				restorePoints.push(active)
+				o.debugf(2, "    Saving Active: %v", o.active.ItemIDs())
+				o.restorePoints.push(o.active)
 				continue
 			}
 
-			if prev := output.Back(); prev != nil && prev.Type == itm.Type && prev.Action == item.STOP {
-				d.debugf(2, "    START immediately preceded by STOP: removing previous %s from output", prev)
-				output.RemoveBack()
-			}
-			active.MoveToBackOrAppend(itm.Clone())
+			o.active.Append(itm.Clone())
+			o.emit(o.output, o.active, itm)
 
 		case item.STOP:
 			if itm.Type == item.SAVE {
-				if rp := restorePoints.pop(); rp != nil {
-					active = rp
-					if active.Len() == 0 {
-						d.debugf(2, "    Restoring Empty Active")
-					} else {
-						d.debugf(2, "    Restoring Active: %v", rp.ItemIDs())
-						input.InsertAfterList(itm, active)
-						p := itm.Prev()
-						input.Remove(itm)
-						itm = p
-					}
+				if rp := o.restorePoints.pop(); rp != nil {
+					o.debugf(2, "    Restoring Active: %v", rp.ItemIDs())
+					o.restore(o.output, o.active, rp)
+					o.active = rp
 				}
 				continue
 			}
 
-			// 1. if prev.Type == itm.Type && prev.Action == START --> Remove prev from output
-			if prev := output.Back(); prev != nil && prev.Type == itm.Type && prev.Action == item.START {
-				d.debugf(2, "    STOP (%s) immediately preceded by START (%s): undoing", itm, prev)
-				output.RemoveBack()
-				d.debugf(3, "    'output' done")
+			rem := o.active.RemoveLast(itm.Type)
+			o.debugf(3, "    Removed %s from active", rem)
+
+			// If an earlier START of the same type is still active, it
+			// is restored instead of turning the attribute off entirely.
+			if prev := o.active.Last(itm.Type); prev != nil {
+				if !prev.Equal(rem) {
+					o.debugf(2, "    STOP (%s) restores previous %s", itm, prev)
+					o.emit(o.output, o.active, prev)
+				}
+				continue
 			}
 
-			// 2. Find the final instance of itm.Type in 'active' and remove it
-			d.debugf(2, "    Updating 'active' and 'output' to remove most recent START:%s", itm.Type)
-			rem := active.RemoveLast(itm.Type)
-			d.debugf(3, "    Removed %s from active", rem)
+			o.emit(o.output, o.active, itm)
+
+		default:
+			o.output.Append(itm.Clone())
 		}
+	}
+}
 
-		output.Append(itm.Clone())
+// emit appends a copy of the attribute Item itm to output while removing any
+// attribute change of the same type that immediately precedes it (since the
+// earlier change would have no effect). If the state itm would establish is
+// already in effect, nothing is appended. If itm turns off all attributes
+// (i.e. 'sgr0') then each of the attributes in active are turned back on.
+func (d *Decorator) emit(output, active *series.Series, itm *item.Item) {
+	if prev := output.Back(); prev != nil && prev.Type == itm.Type && prev.Action != item.NONE {
+		d.debugf(2, "    %s immediately preceded by %s: removing previous from output", itm, prev)
+		output.RemoveBack()
+	}
+
+	if d.inEffect(output, itm) {
+		d.debugf(2, "    %s already in effect: omitting", itm)
+		return
+	}
 
-		if d.isAllOff(itm) && active.Len() > 0 {
-			// If the above has turned everything off (i.e. 'sgr0') then
-			// we'll need to turn all of the 'active' stuff back on.
-			d.debugf(2, "    inserting active list after %q: %v", itm, active.ItemIDs())
-			d.debugf(3, "      before: %v", input.ItemIDs())
-			input.InsertAfterList(itm, active)
-			d.debugf(3, "       after: %v", input.ItemIDs())
+	output.Append(itm.Detach())
+
+	if d.isAllOff(itm) && active.Len() > 0 {
+		// If the above has turned everything off (i.e. 'sgr0') then
+		// we'll need to turn all of the 'active' stuff back on.
+		d.debugf(2, "    re-enabling active list after %q: %v", itm, active.ItemIDs())
+		for a := active.Topmost().Front(); a != nil; a = a.Next() {
+			if isSGR(a.Type) {
+				d.emit(output, active, a)
+			}
 		}
 	}
+}
 
-	return output
+// inEffect returns true if the attribute state established by itm is already
+// in effect at the end of output.
+func (d *Decorator) inEffect(output *series.Series, itm *item.Item) bool {
+	for o := output.Back(); o != nil; o = o.Prev() {
+		if o.Type == itm.Type {
+			return o.Action == itm.Action && (itm.Action == item.STOP || o.Equal(itm))
+		}
+
+		if d.isAllOff(o) && isSGR(itm.Type) {
+			break
+		}
+	}
+
+	// Nothing of this type is in effect.
+	return itm.Action == item.STOP
+}
+
+// isSGR returns true if attributes of the given type are affected by
+// turning off all attributes (i.e. 'sgr0').
+func isSGR(t item.Type) bool {
+	return t != item.LINK
+}
+
+// restore appends to output whatever attribute changes are needed to move
+// from the attributes in active to those in saved.
+func (d *Decorator) restore(output, active, saved *series.Series) {
+	var allOff bool
+
+	for a := active.Topmost().Front(); a != nil; a = a.Next() {
+		if saved.Last(a.Type) == nil {
+			stop := item.StopItem(a.Type)
+			d.emit(output, saved, stop)
+			allOff = allOff || d.isAllOff(stop)
+		}
+	}
+
+	for s := saved.Topmost().Front(); s != nil; s = s.Next() {
+		if allOff && isSGR(s.Type) {
+			// Already re-enabled by emit
+			continue
+		}
+
+		if !s.Equal(active.Last(s.Type)) {
+			d.emit(output, saved, s)
+		}
+	}
 }
 
 type seriesStack struct {
@@ -90,7 +175,12 @@ type seriesStack struct {
 }
 
 func (ss *seriesStack) push(s *series.Series) {
-	ss.stack = append([]*series.Series{s.Clone()}, ss.stack...)
+	c := s.Clone()
+	if c == nil {
+		c = series.New()
+	}
+
+	ss.stack = append([]*series.Series{c}, ss.stack...)
 }
 
 func (ss *seriesStack) pop() *series.Series {
@@ -0,0 +1,63 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"testing"
+)
+
+func TestOptimize(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		label string
+		input string
+		want  string
+	}{
+		{"restore-after-sgr0", "@I@F{44}@Ba@bb@f@i", "" +
+			xt_sitm + "\x1b[38;5;44m\x1b[1ma" +
+			xt_sgr0 + xt_sitm + "\x1b[38;5;44mb" +
+			xt_defFG + xt_ritm},
+		{"start-stop-pair", "a@F{Red}@fb", "ab"},
+		{"superseded-start", "@F{Red}@F{Blue}a@f@f", "\x1b[34ma" + xt_defFG},
+		{"already-in-effect", "@F{Red}a@F{Red}b@fc@f", "\x1b[31mabc" + xt_defFG},
+		{"restored-start", "@F{Red}a@F{Blue}@fb@f", "\x1b[31mab" + xt_defFG},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			got, err := d.Format(tc.input)
+			if err != nil || got != tc.want {
+				t.Errorf("Format(%q) == (%q, %v)\nWanted (%q, nil)", tc.input, decodeAttrString(got), err, decodeAttrString(tc.want))
+			}
+
+			tmpl, err := d.Template(tc.input)
+			if err != nil {
+				t.Fatalf("Template(%q) error: %v", tc.input, err)
+			}
+
+			if exp := tmpl.Expand(nil); exp != got {
+				t.Errorf("Format(%q) and Template.Expand differ:\nFormat: %q\nExpand: %q", tc.input, decodeAttrString(got), decodeAttrString(exp))
+			}
+		})
+	}
+}
+
+func TestFormatf(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := d.Formatf("@I@F{%d}@B%s@b%s@f@i", 44, "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, _ := d.Format("@I@F{44}@Ba@bb@f@i"); got != want {
+		t.Errorf("Formatf(...) == %q; Wanted %q", decodeAttrString(got), decodeAttrString(want))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+// An Option alters the behavior of a Decorator created by New or Load.
+type Option func(*Decorator)
+
+func (d *Decorator) apply(opts []Option) *Decorator {
+	for _, o := range opts {
+		o(d)
+	}
+	return d
+}
+
+// MergeSGR returns an Option causing consecutive attribute changes to be
+// combined into a single ANSI "Select Graphic Rendition" (SGR) sequence
+// wherever possible. For example, "@B@F{44}@I" would be rendered as
+// "\x1b[1;38;5;44;3m" instead of "\x1b[1m\x1b[38;5;44m\x1b[3m". Terminal
+// codes that are not SGR sequences are emitted unaltered.
+func MergeSGR() Option {
+	return func(d *Decorator) { d.mergeSGR = true }
+}
+
+// Hyperlinks returns an Option that enables (or disables) the emission of
+// OSC 8 hyperlinks for the @L designator, overriding what was determined
+// from the terminal type and environment.
+func Hyperlinks(enable bool) Option {
+	return func(d *Decorator) { d.hyperlinks = enable }
+}
+
+// Strict returns an Option causing text containing unknown attribute
+// designators or color names to be rejected with a *ParseError instead of
+// being silently ignored or rendered as an error marker in the output. See
+// also Validate.
+func Strict() Option {
+	return func(d *Decorator) { d.strict = true }
+}
+
+// PromptShell returns an Option causing each run of terminal codes to be
+// wrapped with the zero-width markers required in prompt strings for the
+// given shell (e.g. "%{...%}" for Zsh). See also the Prompt method.
+func PromptShell(shell Shell) Option {
+	return func(d *Decorator) { d.shell = shell }
+}
+
+// Environment returns an Option providing the function used to look up the
+// environment variables referenced by templates (e.g. "${$HOME}") instead of
+// os.LookupEnv; for example, to supply a fake environment for testing.
+func Environment(lookup func(string) (string, bool)) Option {
+	return func(d *Decorator) { d.lookupEnv = lookup }
+}
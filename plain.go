@@ -0,0 +1,47 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// NewFor returns a new *Decorator suitable for output written to w. If w is
+// a terminal (or $CLICOLOR_FORCE is set), this is equivalent to calling New;
+// otherwise, the returned Decorator emits plain text -- that is, its Format
+// method (and the Expand method for its Templates) produce the same output
+// as Strip.
+func NewFor(w io.Writer, opts ...Option) (*Decorator, error) {
+	policy := colorPolicy(os.Getenv)
+
+	if isTerminal(w) || policy == CLIColorForce {
+		return New(opts...)
+	}
+
+	d := &Decorator{
+		term:   os.Getenv("TERM"),
+		policy: policy,
+		plain:  true,
+	}
+
+	return d.apply(opts), nil
+}
+
+// Plain returns true if the receiver emits plain text without any terminal
+// codes (see NewFor).
+func (d *Decorator) Plain() bool {
+	return d != nil && d.plain
+}
+
+// isTerminal returns true if w is a file associated with a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+
+	return term.IsTerminal(int(f.Fd()))
+}
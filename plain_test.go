@@ -0,0 +1,54 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFor(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "")
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Character devices other than terminals are not terminals.
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+
+	for _, w := range []io.Writer{&bytes.Buffer{}, f, null} {
+		d, err := NewFor(w)
+		if err != nil {
+			t.Fatalf("NewFor(%T) error: %v", w, err)
+		}
+
+		if !d.Plain() {
+			t.Errorf("NewFor(%T).Plain() == false; Wanted true", w)
+		}
+
+		input, want := "@B@F{44}@Iuser@i@F{Orchid1}@@@F{Green3}host@f@b", "user@host"
+		if got, err := d.Format(input); err != nil || got != want {
+			t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", input, got, err, want)
+		}
+
+		tmpl, err := d.Template("@F(Grey37)[${Glyph}:@I${Key}@i]@f")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vars := map[string]string{"Glyph": "@F<Orange1>Ж@f", "Key": "@F{204}ABC@f"}
+		if got, want := tmpl.Expand(vars), "[Ж:ABC]"; got != want {
+			t.Errorf("tmpl.Expand(%v) == %q; Wanted %q", vars, got, want)
+		}
+	}
+}
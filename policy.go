@@ -0,0 +1,83 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"github.com/xo/terminfo"
+)
+
+// ColorPolicy describes the color policy a Decorator derived from its
+// environment.
+type ColorPolicy int
+
+const (
+	// ColorAuto indicates colors are displayed as supported by the terminal.
+	ColorAuto ColorPolicy = iota
+
+	// NoColor indicates colors are disabled due to $NO_COLOR.
+	NoColor
+
+	// CLIColorOff indicates colors are disabled due to $CLICOLOR=0.
+	CLIColorOff
+
+	// CLIColorForce indicates colors are forced due to $CLICOLOR_FORCE.
+	CLIColorForce
+)
+
+func (p ColorPolicy) String() string {
+	switch p {
+	case ColorAuto:
+		return "auto"
+	case NoColor:
+		return "NO_COLOR"
+	case CLIColorOff:
+		return "CLICOLOR=0"
+	case CLIColorForce:
+		return "CLICOLOR_FORCE"
+	default:
+		return "<UNKNOWN>"
+	}
+}
+
+// Disabled returns true if the receiver disables the display of colors.
+func (p ColorPolicy) Disabled() bool {
+	return p == NoColor || p == CLIColorOff
+}
+
+// ColorPolicy returns the color policy applied to the receiver. For
+// Decorators created by Load (which ignores the current environment) this
+// is always ColorAuto.
+func (d *Decorator) ColorPolicy() ColorPolicy {
+	if d != nil {
+		return d.policy
+	}
+	return ColorAuto
+}
+
+// colorPolicy returns the ColorPolicy indicated by the $NO_COLOR, $CLICOLOR
+// and $CLICOLOR_FORCE environment variables (as provided by getenv).
+func colorPolicy(getenv func(string) string) ColorPolicy {
+	if getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+
+	if f := getenv("CLICOLOR_FORCE"); f != "" && f != "0" {
+		return CLIColorForce
+	}
+
+	if getenv("CLICOLOR") == "0" {
+		return CLIColorOff
+	}
+
+	return ColorAuto
+}
+
+// applyPolicy records the given ColorPolicy for the receiver and, if it
+// disables colors, clears the receiver's color tables.
+func (d *Decorator) applyPolicy(ti *terminfo.Terminfo, p ColorPolicy) {
+	d.policy = p
+
+	if p.Disabled() {
+		d.setColors(ti, 0)
+	}
+}
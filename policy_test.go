@@ -0,0 +1,50 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"testing"
+)
+
+func TestColorPolicy(t *testing.T) {
+	ti, err := xterm256Terminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "@B@F{Red}x@f@b"
+
+	cases := []struct {
+		env  map[string]string
+		want ColorPolicy
+		out  string
+	}{
+		{map[string]string{}, ColorAuto, "\x1b[1m\x1b[31mx" + xt_defFG + xt_sgr0},
+		{map[string]string{"NO_COLOR": "1"}, NoColor, "\x1b[1mx" + xt_sgr0},
+		{map[string]string{"NO_COLOR": ""}, ColorAuto, "\x1b[1m\x1b[31mx" + xt_defFG + xt_sgr0},
+		{map[string]string{"CLICOLOR": "0"}, CLIColorOff, "\x1b[1mx" + xt_sgr0},
+		{map[string]string{"CLICOLOR": "1"}, ColorAuto, "\x1b[1m\x1b[31mx" + xt_defFG + xt_sgr0},
+		{map[string]string{"CLICOLOR": "0", "CLICOLOR_FORCE": "1"}, CLIColorForce, "\x1b[1m\x1b[31mx" + xt_defFG + xt_sgr0},
+		{map[string]string{"CLICOLOR": "0", "CLICOLOR_FORCE": "0"}, CLIColorOff, "\x1b[1mx" + xt_sgr0},
+		{map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, NoColor, "\x1b[1mx" + xt_sgr0},
+	}
+
+	for _, tc := range cases {
+		p := colorPolicy(func(k string) string { return tc.env[k] })
+		if p != tc.want {
+			t.Errorf("colorPolicy(%v) == %v; Wanted %v", tc.env, p, tc.want)
+			continue
+		}
+
+		d := newDecorator("xterm-256color", ti)
+		d.applyPolicy(ti, p)
+
+		if got := d.ColorPolicy(); got != tc.want {
+			t.Errorf("ColorPolicy() == %v; Wanted %v", got, tc.want)
+		}
+
+		if got, err := d.Format(input); err != nil || got != tc.out {
+			t.Errorf("[%v] Format(%q) == (%q, %v); Wanted (%q, nil)", p, input, got, err, tc.out)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Sprintf formats according to a format specifier (as with fmt.Sprintf) and
+// returns the result decorated for the receiver's terminal type. Only format
+// is interpreted as decor notation; args are inserted literally (i.e. any
+// '@' or '$' characters they contain are not treated as designators). If
+// format cannot be parsed as decor notation, an error message is returned
+// in place of the result.
+func (d *Decorator) Sprintf(format string, args ...any) string {
+	s, err := d.sprintf(format, args)
+	if err != nil {
+		return fmt.Sprintf("<err:%v>", err)
+	}
+
+	return s
+}
+
+// Fprintf is similar to Sprintf but writes its result to w. It returns the
+// number of bytes written and any error encountered (including an error if
+// format cannot be parsed as decor notation).
+func (d *Decorator) Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	s, err := d.sprintf(format, args)
+	if err != nil {
+		return 0, err
+	}
+
+	return io.WriteString(w, s)
+}
+
+// Printf is similar to Fprintf but writes to standard output.
+func (d *Decorator) Printf(format string, args ...any) (int, error) {
+	return d.Fprintf(os.Stdout, format, args...)
+}
+
+// Println writes text (interpreted as decor notation) to standard output
+// followed by the default formats of args (inserted literally). As with
+// fmt.Println, spaces are added between operands and a newline is appended
+// but, unlike fmt.Println, the first operand is always the decor-notated
+// text. It returns the number of bytes written and any error encountered.
+func (d *Decorator) Println(text string, args ...any) (int, error) {
+	s, err := d.Format(text)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(args) > 0 {
+		s += " " + fmt.Sprintln(args...)
+	} else {
+		s += "\n"
+	}
+
+	return io.WriteString(os.Stdout, s)
+}
+
+func (d *Decorator) sprintf(format string, args []any) (string, error) {
+	return d.Format(fmt.Sprintf(format, literalArgs(format, args)...))
+}
+
+var escaper = strings.NewReplacer("@", "@@", "$", "$$")
+
+// literal is a fmt.Formatter that formats its wrapped argument normally
+// then escapes any decor sigils in the result.
+type literal struct {
+	arg any
+}
+
+func (l literal) Format(f fmt.State, verb rune) {
+	io.WriteString(f, escaper.Replace(fmt.Sprintf(directive(f, verb), l.arg)))
+}
+
+// literalArgs wraps each of args so they'll be inserted literally into decor
+// text. The only arguments left as-is are those consumed by format as a '*'
+// width or precision (which must be integers) and those formatted with the
+// %T verb (which would otherwise report the wrapper's type).
+func literalArgs(format string, args []any) []any {
+	out := make([]any, len(args))
+	star, typed := argVerbs(format)
+
+	for i, a := range args {
+		if star[i] || typed[i] {
+			out[i] = a
+		} else {
+			out[i] = literal{a}
+		}
+	}
+
+	return out
+}
+
+// argVerbs returns the sets of argument indexes consumed by format as a '*'
+// width or precision and those formatted using the %T verb.
+func argVerbs(format string) (star, typed map[int]bool) {
+	star = make(map[int]bool)
+	typed = make(map[int]bool)
+
+	var argNum int
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// Scan past flags, argument indexes, widths and precisions
+		// (with '*' consuming an argument) until reaching the verb.
+		for i++; i < len(format); i++ {
+			c := format[i]
+
+			switch {
+			case strings.IndexByte("+-# 0.", c) >= 0 || c >= '1' && c <= '9':
+				continue
+			case c == '*':
+				star[argNum] = true
+				argNum++
+				continue
+			case c == '[':
+				j := strings.IndexByte(format[i:], ']')
+				if j == -1 {
+					return star, typed
+				}
+
+				if n, err := strconv.Atoi(format[i+1 : i+j]); err == nil {
+					argNum = n - 1
+				}
+
+				i += j
+				continue
+			case c == '%':
+				// A literal percent sign
+			default:
+				if c == 'T' {
+					typed[argNum] = true
+				}
+				argNum++
+			}
+
+			break
+		}
+	}
+
+	return star, typed
+}
+
+// directive reconstructs the formatting directive described by f and verb.
+func directive(f fmt.State, verb rune) string {
+	d := "%"
+
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			d += string(flag)
+		}
+	}
+
+	if w, ok := f.Width(); ok {
+		d += strconv.Itoa(w)
+	}
+
+	if p, ok := f.Precision(); ok {
+		d += "." + strconv.Itoa(p)
+	}
+
+	return d + string(verb)
+}
@@ -0,0 +1,53 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSprintf(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		format string
+		args   []any
+		want   string
+	}{
+		{"@F{%d}%s@f", []any{44, "user@example.com"}, "\x1b[38;5;44muser@example.com" + xt_defFG},
+		{"@I%s@i costs %v", []any{"item", "$5"}, xt_sitm + "item" + xt_ritm + " costs $5"},
+		{"[%-6s|%6.2f|%q]", []any{"a@b", 3.14159, "${x}"}, `[a@b   |  3.14|"${x}"]`},
+		{"%*d|%T", []any{5, 42, errors.New("@")}, "   42|*errors.errorString"},
+		{"%*d|%.*f|%x", []any{int64(5), 42, uint8(1), 1.25, uint16(255)}, "   42|1.2|ff"},
+		{"%v", []any{stringerInt(1)}, "a@b"},
+		{"%c%c", []any{'@', 'B'}, "@B"},
+		{"x%cy%c", []any{'$', byte('@')}, "x$y@"},
+		{"%q|%q|%U", []any{'@', '$', '@'}, "'@'|'$'|U+0040"},
+		{"%[2]*[1]d|%d", []any{7, int8(3)}, "  7|3"},
+		{"%v", []any{errors.New("bad @F char")}, "bad @F char"},
+		{"%[2]T|%[1]s", []any{"@", 1.5}, "float64|@"},
+		{"@F{", nil, "<err:unterminated attribute @F at pos 0>"},
+	}
+
+	for _, tc := range cases {
+		if got := d.Sprintf(tc.format, tc.args...); got != tc.want {
+			t.Errorf("Sprintf(%q, %v) == %q; Wanted %q", tc.format, tc.args, got, tc.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	want := "\x1b[1mname:" + xt_sgr0 + " a@b"
+	if n, err := d.Fprintf(&buf, "@Bname:@b %s", "a@b"); err != nil || buf.String() != want || n != len(want) {
+		t.Errorf("Fprintf(...) == (%d, %v) -> %q; Wanted (%d, nil) -> %q", n, err, buf.String(), len(want), want)
+	}
+}
+
+// stringerInt is an integer whose String method returns decor sigils.
+type stringerInt int
+
+func (stringerInt) String() string { return "a@b" }
@@ -0,0 +1,82 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+// Shell identifies a command shell whose prompt strings require terminal
+// codes to be specially marked so the shell can correctly determine the
+// prompt's displayed width (see the PromptShell Option).
+type Shell int
+
+const (
+	// NoShell indicates terminal codes are emitted unaltered.
+	NoShell Shell = iota
+
+	// Zsh wraps terminal codes with "%{" and "%}".
+	Zsh
+
+	// Bash wraps terminal codes with "\[" and "\]".
+	Bash
+
+	// Fish leaves terminal codes unaltered since fish determines a
+	// prompt's width on its own.
+	Fish
+)
+
+func (s Shell) String() string {
+	switch s {
+	case NoShell:
+		return "none"
+	case Zsh:
+		return "zsh"
+	case Bash:
+		return "bash"
+	case Fish:
+		return "fish"
+	default:
+		return "<UNKNOWN>"
+	}
+}
+
+// wrap returns codes wrapped with the receiver's zero-width markers.
+func (s Shell) wrap(codes string) string {
+	if codes == "" {
+		return ""
+	}
+
+	switch s {
+	case Zsh:
+		return "%{" + codes + "%}"
+	case Bash:
+		return `\[` + codes + `\]`
+	default:
+		return codes
+	}
+}
+
+// wrapCodes joins codes (see joinCodes) and wraps the result for the
+// receiver's prompt shell, if any.
+func (d *Decorator) wrapCodes(codes []string) string {
+	var shell Shell
+	if d != nil {
+		shell = d.shell
+	}
+
+	return shell.wrap(d.joinCodes(codes))
+}
+
+// Prompt is like Format but wraps each run of terminal codes as needed for
+// use in a prompt string for the given shell (regardless of the receiver's
+// PromptShell Option). Note that only the terminal codes are altered; any
+// shell prompt escapes (e.g. "%~" or "\w") in text are left for the shell to
+// expand.
+func (d *Decorator) Prompt(shell Shell, text string) (string, error) {
+	if d == nil {
+		// No terminal codes to wrap
+		return d.Format(text)
+	}
+
+	pd := *d
+	pd.shell = shell
+
+	return pd.Format(text)
+}
@@ -0,0 +1,42 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestPrompt(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := `@B@F{44}%n@f@b %~ `
+
+	cases := []struct {
+		shell Shell
+		want  string
+	}{
+		{NoShell, "\x1b[1m\x1b[38;5;44m%n" + xt_defFG + xt_sgr0 + " %~ "},
+		{Zsh, "%{\x1b[1m\x1b[38;5;44m%}%n%{" + xt_defFG + xt_sgr0 + "%} %~ "},
+		{Bash, `\[` + "\x1b[1m\x1b[38;5;44m" + `\]%n\[` + xt_defFG + xt_sgr0 + `\] %~ `},
+		{Fish, "\x1b[1m\x1b[38;5;44m%n" + xt_defFG + xt_sgr0 + " %~ "},
+	}
+
+	for _, tc := range cases {
+		if got, err := d.Prompt(tc.shell, text); err != nil || got != tc.want {
+			t.Errorf("Prompt(%v, %q) == (%q, %v); Wanted (%q, nil)", tc.shell, text, got, err, tc.want)
+		}
+	}
+
+	// Prompt should not alter the receiver...
+	if got, err := d.Format(text); err != nil || got != cases[0].want {
+		t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", text, got, err, cases[0].want)
+	}
+
+	// ...while the PromptShell Option does.
+	d.apply([]Option{PromptShell(Zsh), MergeSGR()})
+	want := "%{\x1b[1;38;5;44m%}%n%{\x1b[39m\x1b(B\x1b[0m%} %~ "
+	if got, err := d.Format(text); err != nil || got != want {
+		t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", text, got, err, want)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "toolman.org/terminal/decor/internal/series"
+
+// A Renderer renders decor-notated text as something other than terminal
+// codes (e.g. HTML). Each Renderer has a Format method for rendering text,
+// which is parsed leniently (as if by a Decorator without the Strict Option),
+// and an Expand method for rendering a Template after expanding its variable
+// values. These are shorthand for the Render methods of Decorator and
+// Template, respectively.
+type Renderer interface {
+	Format(text string) (string, error)
+	Expand(t *Template, values map[string]string) string
+
+	render(ss *series.Series) string
+}
+
+// Render returns the rendering by r of the given decor-notated text, which is
+// parsed according to the receiver's Options (e.g. Strict). The empty string
+// and a *ParseError are returned if text cannot be parsed. As with Format,
+// variable references are ignored.
+func (d *Decorator) Render(r Renderer, text string) (string, error) {
+	ss := series.New()
+
+	if err := d.parse(ss, text); err != nil {
+		return "", err
+	}
+
+	return r.render(ss), nil
+}
+
+// Render returns the rendering by r of the receiver after expanding the
+// given variable values exactly as Expand would.
+func (t *Template) Render(r Renderer, values map[string]string) string {
+	return r.render(t.expand(values))
+}
@@ -4,14 +4,17 @@ package decor
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"toolman.org/terminal/decor/internal/item"
 	"toolman.org/terminal/decor/internal/series"
 )
 
-func (d *Decorator) resolve(name string, values map[string]string) *series.Series {
-	return d.resolver().resolve(name, values)
+// resolve returns the resolved value of the variable reference ref (i.e.
+// the Text of a VAR Item) using the given values.
+func (d *Decorator) resolve(ref string, values map[string]string) *series.Series {
+	return d.resolver().resolveRef(ref, values)
 }
 
 type resolver struct {
@@ -51,22 +54,79 @@ func (cre *crefError) Error() string {
 	return fmt.Sprintf("circular reference: %s", strings.Join(cre.circle, "->"))
 }
 
+// resolveRef resolves the variable reference ref, which may be a simple
+// variable name or a conditional reference of the form "name:-default",
+// "name:+alternate" or "name:?message". As with shell parameter expansion,
+// a variable having an empty value is considered to be unset.
+func (r *resolver) resolveRef(ref string, values map[string]string) *series.Series {
+	name, op, word := item.SplitVarRef(ref)
+	val, _ := r.value(name, values)
+	set := val != ""
+
+	switch {
+	case op == ":-" && !set, op == ":+" && set:
+		r.debugf(1, "  resolving %q for: %q", word, ref)
+		return r.resolveText(name, word, values)
+
+	case op == ":+":
+		return series.New().Append(item.TextItem(""))
+
+	case op == ":?" && !set:
+		if word == "" {
+			word = "parameter null or not set"
+		}
+		return series.New().Append(item.ErrItemf("%s: %s", name, word))
+
+	default:
+		return r.resolve(name, values)
+	}
+}
+
 func (r *resolver) resolve(name string, values map[string]string) *series.Series {
 	r.debugf(1, "  resolving: %q", name)
 
 	ss := series.New()
-	val, ok := values[name]
+	val, ok := r.value(name, values)
 	if !ok {
 		return ss.Append(item.ErrItemf("<undef:%s>", name))
 	}
 
 	r.debugf(1, "      value: %q", val)
 
-	if val == "" {
-		return ss.Append(item.TextItem(""))
+	if val == "" || isEnvRef(name) {
+		// Environment values are always literal text
+		return ss.Append(item.TextItem(val))
+	}
+
+	return r.resolveText(name, val, values)
+}
+
+// value returns the value of the named variable and whether it was found.
+// Names beginning with '$' refer to environment variables.
+func (r *resolver) value(name string, values map[string]string) (string, bool) {
+	if !isEnvRef(name) {
+		val, ok := values[name]
+		return val, ok
 	}
 
-	if err := ss.Parse(val); err != nil {
+	if r.lookupEnv != nil {
+		return r.lookupEnv(name[1:])
+	}
+
+	return os.LookupEnv(name[1:])
+}
+
+// isEnvRef returns true if name refers to an environment variable.
+func isEnvRef(name string) bool {
+	return strings.HasPrefix(name, "$")
+}
+
+// resolveText parses text (the value of, or a default for, the variable
+// name) and resolves any variable references it contains.
+func (r *resolver) resolveText(name, text string, values map[string]string) *series.Series {
+	ss := series.New()
+
+	if err := r.parse(ss, text); err != nil {
 		return ss.Append(item.ErrItem(err))
 	}
 
@@ -76,12 +136,13 @@ func (r *resolver) resolve(name string, values map[string]string) *series.Series
 	for itm := ss.Front().Clone(); itm != nil; itm = itm.Next() {
 		r.debugf(2, "  ## %s", itm)
 		if itm.Type == item.VAR {
-			if err := r.checkRefs(itm.Text); err != nil {
+			vname, _, _ := item.SplitVarRef(itm.Text)
+			if err := r.checkRefs(vname); err != nil {
 				out.Append(item.ErrItem(err))
 			} else {
-				r.refs[itm.Text] = name
-				defer delete(r.refs, itm.Text)
-				out.AppendList(r.resolve(itm.Text, values))
+				r.refs[vname] = name
+				defer delete(r.refs, vname)
+				out.AppendList(r.resolveRef(itm.Text, values))
 			}
 			continue
 		}
@@ -0,0 +1,131 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"strings"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Screen renders decor-notated text using the string escapes of GNU screen
+// (e.g. for its hardstatus or caption lines) instead of terminal codes. Each
+// change of attributes is rendered as a "%{=attrs bf}" escape, where attrs
+// holds screen's attribute letters and b and f are the background and
+// foreground color letters. Since screen only supports the 16 system colors
+// by name, other colors are rendered as the nearest of these. Italics,
+// strikethrough, hidden text, underline colors and hyperlinks (@L) are
+// omitted. Literal '%' characters are escaped as "%%".
+type Screen struct{}
+
+// Format returns the screen rendering of text (see Renderer).
+func (sc *Screen) Format(text string) (string, error) {
+	var d *Decorator
+	return d.Render(sc, text)
+}
+
+// Expand returns the screen rendering of t (see Renderer).
+func (sc *Screen) Expand(t *Template, values map[string]string) string {
+	return t.Render(sc, values)
+}
+
+func (sc *Screen) render(ss *series.Series) string {
+	var (
+		out   strings.Builder
+		prev  = screenDefault
+		state = newAttrState()
+	)
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type != item.TEXT && itm.Type != item.ERROR {
+			state.update(itm)
+			continue
+		}
+
+		if itm.Text == "" {
+			continue
+		}
+
+		if cur := screenStyle(state.current()); cur != prev {
+			out.WriteString("%{" + cur + "}")
+			prev = cur
+		}
+
+		out.WriteString(strings.ReplaceAll(itm.Text, "%", "%%"))
+	}
+
+	// As with Tmux, attribute changes following the final text are still
+	// emitted.
+	if cur := screenStyle(state.current()); cur != prev {
+		out.WriteString("%{" + cur + "}")
+	}
+
+	return out.String()
+}
+
+// screenDefault is the screen style having no attributes and the default
+// colors.
+const screenDefault = "= dd"
+
+// screenFlags lists simple attributes along with their screen attribute
+// letters, in the order they're emitted.
+var screenFlags = []struct {
+	t      item.Type
+	letter byte
+}{
+	{item.DIM, 'd'},
+	{item.UNDERLINE, 'u'},
+	{item.BOLD, 'b'},
+	{item.REVERSE, 'r'},
+	{item.STANDOUT, 's'},
+	{item.BLINK, 'B'},
+}
+
+// screenColorLetters holds the screen color letters for the 8 normal system
+// colors; their bright counterparts use the same letters capitalized.
+const screenColorLetters = "krgybmcw"
+
+// screenStyle returns the screen attribute/color modifier (i.e. the contents
+// of a "%{...}" escape) setting exactly the attributes in attrs.
+func screenStyle(attrs map[item.Type]string) string {
+	var b strings.Builder
+
+	b.WriteByte('=')
+	for _, f := range screenFlags {
+		if _, ok := attrs[f.t]; ok {
+			b.WriteByte(f.letter)
+		}
+	}
+
+	b.WriteByte(' ')
+	b.WriteByte(screenColor(attrs, item.BGCOLOR))
+	b.WriteByte(screenColor(attrs, item.FGCOLOR))
+
+	return b.String()
+}
+
+// screenColor returns the screen color letter for the color attribute t in
+// attrs or 'd' (i.e. the default color) if it's unset or cannot be resolved.
+func screenColor(attrs map[item.Type]string, t item.Type) byte {
+	clr, ok := attrs[t]
+	if !ok {
+		return 'd'
+	}
+
+	n, ok := paletteNumber(clr)
+	if !ok || n >= 16 {
+		rgb, ok := resolveColor(clr)
+		if !ok {
+			return 'd'
+		}
+		n = int(color.Nearest(rgb, 16))
+	}
+
+	if n >= 8 {
+		return screenColorLetters[n-8] - 'a' + 'A'
+	}
+
+	return screenColorLetters[n]
+}
@@ -0,0 +1,25 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestScreen(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"100% @Bbold@b", "100%% %{=b dd}bold%{= dd}"},
+		{"@F{Red1}@K{BLUE}@U{curly}x@u@k@f", "%{=u bR}x%{= dd}"},
+		{"@B@F{44}user@f@F{Green3}@@@S@Nhost@n@s@f@b", "%{=b dc}user%{=b dg}@%{=bsB dg}host%{= dd}"},
+		{"@K{#ff8800}a@Ib@i@Xc@x@k", "%{= yd}abc%{= dd}"},
+		{"@L{https://x.y}@D@Rlink@r@d@l", "%{=dr dd}link%{= dd}"},
+		{"plain @F{Bogus}text@f", "plain text"},
+	}
+
+	for _, tc := range cases {
+		if got, err := (&Screen{}).Format(tc.text); err != nil || got != tc.want {
+			t.Errorf("Screen.Format(%q) == (%q, %v); Wanted (%q, nil)", tc.text, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sgrPattern matches a terminal code ending with an ANSI SGR sequence. The
+// first submatch is any preceding text and the second holds the sequence's
+// parameters.
+var sgrPattern = regexp.MustCompile(`^(.*?)\x1b\[([0-9;:]*)m$`)
+
+// joinCodes concatenates the given terminal codes. If the receiver has the
+// MergeSGR option enabled then consecutive SGR sequences are combined into
+// a single sequence.
+func (d *Decorator) joinCodes(codes []string) string {
+	if d == nil || !d.mergeSGR {
+		return strings.Join(codes, "")
+	}
+
+	var (
+		out    string
+		params []string
+	)
+
+	flush := func() {
+		if len(params) > 0 {
+			out += "\x1b[" + strings.Join(params, ";") + "m"
+			params = nil
+		}
+	}
+
+	for _, code := range codes {
+		if code == "" {
+			continue
+		}
+
+		m := sgrPattern.FindStringSubmatch(code)
+		if m == nil {
+			flush()
+			out += code
+			continue
+		}
+
+		if m[1] != "" {
+			// e.g. the "\x1b(B" that precedes 'sgr0' for xterm
+			flush()
+			out += m[1]
+		}
+
+		p := m[2]
+		if p == "" {
+			p = "0"
+		}
+
+		params = append(params, p)
+	}
+
+	flush()
+
+	return out
+}
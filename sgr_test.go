@@ -0,0 +1,32 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"testing"
+)
+
+func TestMergeSGR(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.apply([]Option{MergeSGR()})
+
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"@B@F{44}@Iuser@i@b@f", "\x1b[1;38;5;44;3muser\x1b[23m\x1b(B\x1b[0m"},
+		{"@I@F{44}@Ba@bb@f@i", "\x1b[3;38;5;44;1ma\x1b(B\x1b[0;3;38;5;44mb\x1b[39;23m"},
+		{"@F(Grey37)[ABC:@I123@i]@f", "\x1b[38;5;59m[ABC:\x1b[3m123\x1b[23m]\x1b[39m"},
+		{"plain", "plain"},
+	}
+
+	for _, tc := range cases {
+		if got, err := d.Format(tc.input); err != nil || got != tc.want {
+			t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", tc.input, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// attrState tracks the attributes in effect while walking a Series, using the
+// same nesting and SAVE/RESTORE semantics as the optimizer. It's used by the
+// renderers that, rather than emitting attribute changes as they occur, need
+// to know the complete set of attributes applied to each bit of text.
+type attrState struct {
+	active        *series.Series
+	restorePoints seriesStack
+}
+
+func newAttrState() *attrState {
+	return &attrState{active: series.New()}
+}
+
+// update applies the attribute (or SAVE/RESTORE) Item itm to the receiver.
+// Items of any other kind are ignored.
+func (s *attrState) update(itm *item.Item) {
+	switch {
+	case itm.Type == item.SAVE && itm.Action == item.START:
+		s.restorePoints.push(s.active)
+	case itm.Type == item.SAVE && itm.Action == item.STOP:
+		if rp := s.restorePoints.pop(); rp != nil {
+			s.active = rp
+		}
+	case itm.Action == item.START:
+		s.active.Append(itm.Clone())
+	case itm.Action == item.STOP:
+		s.active.RemoveLast(itm.Type)
+	}
+}
+
+// current returns the attributes currently in effect mapped to their
+// argument (e.g. a color name), which is empty for simple attributes.
+func (s *attrState) current() map[item.Type]string {
+	cur := make(map[item.Type]string)
+
+	for a := s.active.Topmost().Front(); a != nil; a = a.Next() {
+		cur[a.Type] = a.Text
+	}
+
+	return cur
+}
@@ -0,0 +1,230 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// SVG renders decor-notated text as a standalone SVG image resembling its
+// display on a terminal (e.g. for documentation screenshots). Text is drawn
+// using a monospace font with each line of text on its own row (and columns
+// measured as described for Width), colors are drawn from the xterm palette
+// and hyperlinks (@L) become <a> elements (subject to the same restrictions
+// as for HTML).
+//
+// The zero value renders dark text on a light background at a font size of
+// 14 pixels.
+type SVG struct {
+	// Dark, if true, selects light text on a dark background.
+	Dark bool
+
+	// FontSize is the font size in pixels; zero means 14.
+	FontSize int
+
+	// FontFamily is the font used for all text; the empty string means
+	// "monospace".
+	FontFamily string
+}
+
+// Format returns the SVG rendering of text (see Renderer).
+func (s *SVG) Format(text string) (string, error) {
+	var d *Decorator
+	return d.Render(s, text)
+}
+
+// Expand returns the SVG rendering of t (see Renderer).
+func (s *SVG) Expand(t *Template, values map[string]string) string {
+	return t.Render(s, values)
+}
+
+// svgRun is a run of text sharing the same attributes.
+type svgRun struct {
+	text  string
+	col   int
+	attrs map[item.Type]string
+}
+
+func (s *SVG) render(ss *series.Series) string {
+	var (
+		lines = [][]svgRun{nil}
+		col   int
+		cols  int
+		state = newAttrState()
+	)
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type != item.TEXT && itm.Type != item.ERROR {
+			state.update(itm)
+			continue
+		}
+
+		for i, text := range strings.Split(itm.Text, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+				col = 0
+			}
+
+			if text == "" {
+				continue
+			}
+
+			text, next := expandTabs(text, col)
+
+			n := len(lines) - 1
+			lines[n] = append(lines[n], svgRun{text, col, state.current()})
+
+			if col = next; col > cols {
+				cols = col
+			}
+		}
+	}
+
+	fontSize := s.FontSize
+	if fontSize <= 0 {
+		fontSize = 14
+	}
+
+	family := s.FontFamily
+	if family == "" {
+		family = "monospace"
+	}
+
+	fg, bg := color.Value(0).String(), color.Value(15).String()
+	if s.Dark {
+		fg, bg = color.Value(7).String(), color.Value(0).String()
+	}
+
+	// All dimensions are in tenths of a pixel: each character cell is 0.6em
+	// wide and 1.2em high with a 1em margin all around.
+	var (
+		cellW = fontSize * 6
+		cellH = fontSize * 12
+		pad   = fontSize * 10
+	)
+
+	var out strings.Builder
+
+	w, h := 2*pad+cols*cellW, 2*pad+len(lines)*cellH
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s" font-family="%s" font-size="%d">`+"\n",
+		px(w), px(h), html.EscapeString(family), fontSize)
+	fmt.Fprintf(&out, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", bg)
+
+	// Backgrounds are drawn first so they don't obscure any text.
+	for row, line := range lines {
+		for _, r := range line {
+			if _, c := svgColors(r.attrs, fg, bg); c != bg {
+				fmt.Fprintf(&out, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+					px(pad+r.col*cellW), px(pad+row*cellH), px(stringWidth(r.text)*cellW), px(cellH), c)
+			}
+		}
+	}
+
+	for row, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&out, `<text y="%s" xml:space="preserve" fill="%s">`, px(pad+row*cellH+fontSize*10), fg)
+
+		for _, r := range line {
+			link := r.attrs[item.LINK]
+			if !safeLink(link) {
+				link = ""
+			}
+
+			if link != "" {
+				fmt.Fprintf(&out, `<a href="%s">`, html.EscapeString(link))
+			}
+
+			fmt.Fprintf(&out, `<tspan x="%s"%s>%s</tspan>`, px(pad+r.col*cellW), svgAttributes(r.attrs, fg, bg), html.EscapeString(r.text))
+
+			if link != "" {
+				out.WriteString("</a>")
+			}
+		}
+
+		out.WriteString("</text>\n")
+	}
+
+	out.WriteString("</svg>\n")
+
+	return out.String()
+}
+
+// svgColors returns the foreground and background colors for attrs, given
+// the default colors fg and bg.
+func svgColors(attrs map[item.Type]string, fg, bg string) (string, string) {
+	if rgb, ok := resolveColor(attrs[item.FGCOLOR]); ok {
+		fg = rgb.String()
+	}
+
+	if rgb, ok := resolveColor(attrs[item.BGCOLOR]); ok {
+		bg = rgb.String()
+	}
+
+	_, rev := attrs[item.REVERSE]
+	_, so := attrs[item.STANDOUT]
+	if rev || so {
+		fg, bg = bg, fg
+	}
+
+	return fg, bg
+}
+
+// svgAttributes returns the SVG presentation attributes (with a leading
+// space) for the text attributes in attrs.
+func svgAttributes(attrs map[item.Type]string, fg, bg string) string {
+	var a []string
+
+	if c, _ := svgColors(attrs, fg, bg); c != fg {
+		a = append(a, `fill="`+c+`"`)
+	}
+
+	if _, ok := attrs[item.BOLD]; ok {
+		a = append(a, `font-weight="bold"`)
+	}
+
+	if _, ok := attrs[item.ITALIC]; ok {
+		a = append(a, `font-style="italic"`)
+	}
+
+	if _, ok := attrs[item.DIM]; ok {
+		a = append(a, `opacity="0.5"`)
+	}
+
+	var deco []string
+	if _, ok := attrs[item.UNDERLINE]; ok {
+		deco = append(deco, "underline")
+	}
+	if _, ok := attrs[item.STRIKE]; ok {
+		deco = append(deco, "line-through")
+	}
+	if len(deco) != 0 {
+		a = append(a, `text-decoration="`+strings.Join(deco, " ")+`"`)
+	}
+
+	if _, ok := attrs[item.INVISIBLE]; ok {
+		a = append(a, `visibility="hidden"`)
+	}
+
+	if len(a) == 0 {
+		return ""
+	}
+
+	return " " + strings.Join(a, " ")
+}
+
+// px formats a dimension given in tenths of a pixel.
+func px(tenths int) string {
+	if tenths%10 == 0 {
+		return fmt.Sprint(tenths / 10)
+	}
+	return fmt.Sprintf("%d.%d", tenths/10, tenths%10)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestSVG(t *testing.T) {
+	text := "@Bok@b <&>\n@K{Red1}@F{44}x@f@k @R@Uy@u@r"
+
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="78.4" height="61.6" viewBox="0 0 78.4 61.6" font-family="monospace" font-size="14">
+<rect width="100%" height="100%" fill="#000000"/>
+<rect x="14" y="30.8" width="8.4" height="16.8" fill="#ff0000"/>
+<rect x="30.8" y="30.8" width="8.4" height="16.8" fill="#e5e5e5"/>
+<text y="28" xml:space="preserve" fill="#e5e5e5"><tspan x="14" font-weight="bold">ok</tspan><tspan x="30.8"> &lt;&amp;&gt;</tspan></text>
+<text y="44.8" xml:space="preserve" fill="#e5e5e5"><tspan x="14" fill="#00d7d7">x</tspan><tspan x="22.4"> </tspan><tspan x="30.8" fill="#000000" text-decoration="underline">y</tspan></text>
+</svg>
+`
+
+	if got, err := (&SVG{Dark: true}).Format(text); err != nil || got != want {
+		t.Errorf("SVG.Format(%q) == (%q, %v); Wanted (%q, nil)", text, got, err, want)
+	}
+
+	link := "@L{https://x.y}@F{BLUE}go@f@l@L{javascript:x}!@l"
+	want = `<svg xmlns="http://www.w3.org/2000/svg" width="60.8" height="51.2" viewBox="0 0 60.8 51.2" font-family="Menlo" font-size="16">
+<rect width="100%" height="100%" fill="#ffffff"/>
+<text y="32" xml:space="preserve" fill="#000000"><a href="https://x.y"><tspan x="16" fill="#0000ee">go</tspan></a><tspan x="35.2">!</tspan></text>
+</svg>
+`
+
+	if got, err := (&SVG{FontSize: 16, FontFamily: "Menlo"}).Format(link); err != nil || got != want {
+		t.Errorf("SVG.Format(%q) == (%q, %v); Wanted (%q, nil)", link, got, err, want)
+	}
+
+	wide := "@K{Red1}日本@kx"
+	want = `<svg xmlns="http://www.w3.org/2000/svg" width="70" height="44.8" viewBox="0 0 70 44.8" font-family="monospace" font-size="14">
+<rect width="100%" height="100%" fill="#000000"/>
+<rect x="14" y="14" width="33.6" height="16.8" fill="#ff0000"/>
+<text y="28" xml:space="preserve" fill="#e5e5e5"><tspan x="14">日本</tspan><tspan x="47.6">x</tspan></text>
+</svg>
+`
+
+	if got, err := (&SVG{Dark: true}).Format(wide); err != nil || got != want {
+		t.Errorf("SVG.Format(%q) == (%q, %v); Wanted (%q, nil)", wide, got, err, want)
+	}
+}
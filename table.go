@@ -0,0 +1,168 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"io"
+	"strings"
+
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Table lays out rows of decor-notated cells in columns aligned by their
+// displayed width (as measured by Width), unlike text/tabwriter which also
+// counts the bytes of terminal codes. Rows are accumulated by Append and
+// written, formatted by the Table's Decorator, when Flush is called.
+//
+// Cells are expected to hold a single line of text. Any attributes still in
+// effect at the end of a cell are stopped there.
+type Table struct {
+	w      io.Writer
+	d      *Decorator
+	header []string
+	style  string
+	align  []Alignment
+	sep    string
+	rows   [][]string
+	headed bool
+}
+
+// NewTable returns a new *Table writing rows to w as formatted by d. By
+// default, columns are left aligned and separated by two spaces.
+func NewTable(w io.Writer, d *Decorator) *Table {
+	return &Table{w: w, d: d, sep: "  "}
+}
+
+// SetHeader sets the cells of a header row displayed before all other rows
+// (with the style set by SetHeaderStyle) by the first call to Flush. The
+// receiver is returned.
+func (t *Table) SetHeader(cells ...string) *Table {
+	t.header = cells
+	return t
+}
+
+// SetHeaderStyle sets the decor attributes (e.g. "@B@U") with which each
+// header cell is displayed. These are automatically stopped at the end of
+// each cell. The receiver is returned.
+func (t *Table) SetHeaderStyle(style string) *Table {
+	t.style = style
+	return t
+}
+
+// SetAlignment sets the alignment of each column in order. Columns without
+// an Alignment are left aligned. The receiver is returned.
+func (t *Table) SetAlignment(align ...Alignment) *Table {
+	t.align = align
+	return t
+}
+
+// SetSeparator sets the decor-notated text displayed between columns. The
+// receiver is returned.
+func (t *Table) SetSeparator(sep string) *Table {
+	t.sep = sep
+	return t
+}
+
+// Append adds a row of cells to the receiver.
+func (t *Table) Append(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Flush writes all rows appended since the previous call to Flush (preceded
+// by the header on the first call) aligned into columns. An error is returned
+// if any cell cannot be parsed as decor notation or if the underlying
+// io.Writer returns an error.
+func (t *Table) Flush() error {
+	rows := t.rows
+	t.rows = nil
+
+	if t.header != nil && !t.headed {
+		rows = append([][]string{t.styleHeader()}, rows...)
+		t.headed = true
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for c, cell := range row {
+			if c == len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if w := Width(cell); w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+
+		for c, cell := range row {
+			align := AlignLeft
+			if c < len(t.align) {
+				align = t.align[c]
+			}
+
+			stops, err := closeAttrs(cell)
+			if err != nil {
+				return err
+			}
+
+			padded, err := Pad(cell+stops, widths[c], align)
+			if err != nil {
+				return err
+			}
+
+			// Don't add trailing spaces to the final cell
+			if c == len(row)-1 {
+				padded = strings.TrimRight(padded, " ")
+			}
+
+			cells[c] = padded
+		}
+
+		line, err := t.d.Format(strings.Join(cells, t.sep))
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(t.w, line+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// styleHeader returns the receiver's header cells prefixed by its header
+// style.
+func (t *Table) styleHeader() []string {
+	styled := make([]string, len(t.header))
+	for c, cell := range t.header {
+		styled[c] = t.style + cell
+	}
+
+	return styled
+}
+
+// closeAttrs returns the decor notation stopping each attribute still in
+// effect at the end of text.
+func closeAttrs(text string) (string, error) {
+	ss := series.New()
+	if err := ss.Parse(text); err != nil {
+		return "", err
+	}
+
+	state := newAttrState()
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		state.update(itm)
+	}
+
+	stops := series.New()
+	for a := state.active.Back(); a != nil; a = a.Prev() {
+		stops.Append(item.StopItem(a.Type))
+	}
+
+	return stops.Notation(), nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	tbl := NewTable(&buf, d).
+		SetHeader("NAME", "STATUS", "AGE").
+		SetHeaderStyle("@B").
+		SetAlignment(AlignLeft, AlignCenter, AlignRight)
+
+	tbl.Append("web-日本", "@F{Green3}ok@f", "3d")
+	tbl.Append("db", "@F{Red1}failed@f", "12d")
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\x1b[1mNAME" + xt_sgr0 + "      \x1b[1mSTATUS" + xt_sgr0 + "  \x1b[1mAGE" + xt_sgr0 + "\n" +
+		"web-日本    \x1b[38;5;40mok" + xt_defFG + "     3d\n" +
+		"db        \x1b[38;5;196mfailed" + xt_defFG + "  12d\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Table output:\n%q\nWanted:\n%q", got, want)
+	}
+
+	for _, line := range bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n")) {
+		if w := d.Width(string(line)); w != 21 {
+			t.Errorf("Width(%q) == %d; Wanted 21", line, w)
+		}
+	}
+
+	buf.Reset()
+	tbl = NewTable(&buf, d)
+	tbl.Append("a\tb", "c")
+	tbl.Append("d", "e\tf")
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "a       b  c\nd          e       f\n"; got != want {
+		t.Errorf("Table output:\n%q\nWanted:\n%q", got, want)
+	}
+
+	buf.Reset()
+	tbl = NewTable(&buf, d).SetHeader("A", "B")
+	tbl.Append("@F{Red}unclosed", "next")
+	tbl.Append("x")
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tbl.Append("@K{Blue}y", "z")
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want = "A         B\n" +
+		"\x1b[31munclosed" + xt_defFG + "  next\n" +
+		"x\n" +
+		"\x1b[44my" + xt_defBG + "  z\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Table output:\n%q\nWanted:\n%q", got, want)
+	}
+
+	buf.Reset()
+	tbl = NewTable(&buf, d).SetSeparator(" | ")
+	tbl.Append("a", "@F{b")
+
+	if err := tbl.Flush(); err == nil {
+		t.Errorf("Flush() succeeded with malformed cell; Wanted error")
+	}
+}
@@ -20,7 +20,7 @@ type Template struct {
 func (d *Decorator) Template(text string) (*Template, error) {
 	ss := series.New()
 
-	if err := ss.Parse(text); err != nil {
+	if err := d.parse(ss, text); err != nil {
 		return nil, err
 	}
 
@@ -33,6 +33,13 @@ func (d *Decorator) Template(text string) (*Template, error) {
 // references are not allowed and will be rendered as errors in the output
 // string.
 func (t *Template) Expand(values map[string]string) string {
+	return t.dec.format(t.dec.optimize(t.expand(values)))
+}
+
+// expand returns a new Series from the receiver's items with each variable
+// reference replaced by its resolved value, bracketed by SAVE and RESTORE
+// items.
+func (t *Template) expand(values map[string]string) *series.Series {
 	ss := series.New()
 
 	t.dec.debugf(1, "template: formatting %d items", t.ss.Len())
@@ -49,7 +56,7 @@ func (t *Template) Expand(values map[string]string) string {
 		}
 	}
 
-	return t.dec.format(t.dec.optimize(ss))
+	return ss
 }
 
 /*
@@ -58,3 +58,139 @@ func TestTemplate(t *testing.T) {
 func (t *Template) equal(os *series.Series) bool {
 	return t.ss.Equal(os)
 }
+
+func TestConditionalRefs(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]string{
+		"Set":   "value",
+		"Empty": "",
+		"Other": "@Iother@i",
+	}
+
+	cases := []struct {
+		tmpl string
+		want string // equivalent decor text
+	}{
+		{"[${Set:-none}]", "[value]"},
+		{"[${Unset:-none}]", "[none]"},
+		{"[${Empty:-@F{Red1}none@f}]", "[@F{Red1}none@f]"},
+		{"[${Unset:-${Other}!}]", "[@Iother@i!]"},
+		{"[${Unset:-${Also:-deep}}]", "[deep]"},
+		{"[${Set:+@B${Set}@b}]", "[@Bvalue@b]"},
+		{"[${Empty:+alt}]", "[]"},
+		{"[${Unset:+alt}]", "[]"},
+		{"[${Set:?required}]", "[value]"},
+	}
+
+	for _, tc := range cases {
+		tmpl, err := d.Template(tc.tmpl)
+		if err != nil {
+			t.Errorf("Template(%q) error: %v", tc.tmpl, err)
+			continue
+		}
+
+		want, err := d.Format(tc.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := tmpl.Expand(vars); got != want {
+			t.Errorf("Template(%q).Expand(...) == %q; Wanted %q", tc.tmpl, got, want)
+		}
+	}
+
+	for tmpl, want := range map[string]string{
+		"${Unset:?is required}": "<err:Unset: is required>",
+		"${Empty:?}":            "<err:Empty: parameter null or not set>",
+	} {
+		tm, err := d.Template(tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := tm.Expand(vars); got != want {
+			t.Errorf("Template(%q).Expand(...) == %q; Wanted %q", tmpl, got, want)
+		}
+	}
+}
+
+func TestEnvironmentRefs(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"HOME":  "/home/user",
+		"EMAIL": "user@example.com",
+		"EMPTY": "",
+	}
+
+	d.apply([]Option{Environment(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})})
+
+	vars := map[string]string{
+		"HOME": "not-the-environment",
+		"Dir":  "@F{44}${$HOME}@f",
+	}
+
+	cases := []struct {
+		tmpl string
+		want string // equivalent decor text
+	}{
+		{"${$HOME}", "/home/user"},
+		{"${HOME}", "not-the-environment"},
+		{"<${$EMAIL}>", "<user@@example.com>"},
+		{"[${Dir}]", "[@F{44}/home/user@f]"},
+		{"${$EDITOR:-vi}", "vi"},
+		{"${$EMPTY:-@Bnone@b}", "@Bnone@b"},
+		{"${$HOME:+@Ihome@i}", "@Ihome@i"},
+	}
+
+	for _, tc := range cases {
+		tmpl, err := d.Template(tc.tmpl)
+		if err != nil {
+			t.Errorf("Template(%q) error: %v", tc.tmpl, err)
+			continue
+		}
+
+		want, err := d.Format(tc.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := tmpl.Expand(vars); got != want {
+			t.Errorf("Template(%q).Expand(...) == %q; Wanted %q", tc.tmpl, got, want)
+		}
+	}
+
+	tmpl, err := d.Template("${$UNSET}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tmpl.Expand(nil), "<err:<undef:$UNSET>>"; got != want {
+		t.Errorf("Template(%q).Expand(nil) == %q; Wanted %q", "${$UNSET}", got, want)
+	}
+
+	// Without the Environment Option, the process environment is used.
+	t.Setenv("DECOR_TEST_VAR", "a@b")
+
+	if d, err = xterm256Decorator(); err != nil {
+		t.Fatal(err)
+	}
+
+	if tmpl, err = d.Template("${$DECOR_TEST_VAR}"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tmpl.Expand(nil), "a@b"; got != want {
+		t.Errorf("Template(%q).Expand(nil) == %q; Wanted %q", "${$DECOR_TEST_VAR}", got, want)
+	}
+}
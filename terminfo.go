@@ -0,0 +1,42 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"github.com/xo/terminfo"
+)
+
+// hasExtBool returns true if the given Terminfo has the named extended
+// boolean capability.
+func hasExtBool(ti *terminfo.Terminfo, name string) bool {
+	for k, v := range ti.ExtBoolNames {
+		if string(v) == name {
+			return ti.ExtBools[k]
+		}
+	}
+
+	return false
+}
+
+// extString returns the named extended string capability from the given
+// Terminfo, formatted with the provided parameters, or the empty string if
+// the capability does not exist.
+func extString(ti *terminfo.Terminfo, name string, params ...any) string {
+	if s := extStringCap(ti, name); s != nil {
+		return terminfo.Printf(s, params...)
+	}
+
+	return ""
+}
+
+// extStringCap returns the raw (unformatted) value for the named extended
+// string capability from the given Terminfo, or nil if it does not exist.
+func extStringCap(ti *terminfo.Terminfo, name string) []byte {
+	for k, v := range ti.ExtStringNames {
+		if string(v) == name {
+			return ti.ExtStrings[k]
+		}
+	}
+
+	return nil
+}
@@ -45,6 +45,7 @@ func decodeAttrString(in string) string {
 		out += in[:x]
 		in = in[x:]
 
+		var found bool
 		for k, v := range amap {
 			nin := strings.TrimPrefix(in, v)
 			if in == nin {
@@ -53,8 +54,15 @@ func decodeAttrString(in string) string {
 
 			out += fmt.Sprintf("<%s>", k)
 			in = nin
+			found = true
 			break
 		}
+
+		if !found {
+			// Unknown sequences are left as-is.
+			out += in[:1]
+			in = in[1:]
+		}
 	}
 
 	return out
@@ -63,17 +71,23 @@ func decodeAttrString(in string) string {
 // xterm256Decorator returns a *Decorator of a known terminal type that
 // is suitable for testing.
 func xterm256Decorator() (*Decorator, error) {
-	data, err := base64.StdEncoding.DecodeString(xterm256color)
+	ti, err := xterm256Terminfo()
 	if err != nil {
 		return nil, err
 	}
 
-	ti, err := terminfo.Decode(data)
+	return newDecorator("xterm-256color", ti), nil
+}
+
+// xterm256Terminfo returns the decoded terminfo entry for the known terminal
+// type used for testing.
+func xterm256Terminfo() (*terminfo.Terminfo, error) {
+	data, err := base64.StdEncoding.DecodeString(xterm256color)
 	if err != nil {
 		return nil, err
 	}
 
-	return newDecorator("xterm-256color", ti), nil
+	return terminfo.Decode(data)
 }
 
 // So unit tests may execute against a known terminal definition, we
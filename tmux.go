@@ -0,0 +1,157 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+	"strings"
+
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Tmux renders decor-notated text using the style syntax of tmux status
+// lines (e.g. "#[fg=colour44,bold]") instead of terminal codes. Colors are
+// rendered as tmux "colourN" names (or "#rrggbb" for direct colors), standout
+// is rendered as reverse video and hyperlinks (@L) are omitted. Literal '#'
+// characters are escaped as "##".
+type Tmux struct{}
+
+// Format returns the tmux rendering of text (see Renderer).
+func (tm *Tmux) Format(text string) (string, error) {
+	var d *Decorator
+	return d.Render(tm, text)
+}
+
+// Expand returns the tmux rendering of t (see Renderer).
+func (tm *Tmux) Expand(t *Template, values map[string]string) string {
+	return t.Render(tm, values)
+}
+
+func (tm *Tmux) render(ss *series.Series) string {
+	var (
+		out   strings.Builder
+		prev  = map[string]string{}
+		state = newAttrState()
+	)
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type != item.TEXT && itm.Type != item.ERROR {
+			state.update(itm)
+			continue
+		}
+
+		if itm.Text == "" {
+			continue
+		}
+
+		cur := tmuxStyle(state.current())
+		if style := tmuxChanges(prev, cur); style != "" {
+			out.WriteString("#[" + style + "]")
+		}
+		prev = cur
+
+		out.WriteString(strings.ReplaceAll(itm.Text, "#", "##"))
+	}
+
+	// Attribute changes following the final text are still emitted so
+	// that, e.g., a trailing "@b" isn't lost.
+	if style := tmuxChanges(prev, tmuxStyle(state.current())); style != "" {
+		out.WriteString("#[" + style + "]")
+	}
+
+	return out.String()
+}
+
+// tmuxKeys lists the keys of a tmux style map in the order they're emitted.
+var tmuxKeys = []string{"fg", "bg", "us", "bold", "dim", "italics", "underscore", "blink", "reverse", "hidden", "strikethrough"}
+
+// tmuxFlags maps simple attributes to their tmux style keywords.
+var tmuxFlags = map[item.Type]string{
+	item.BOLD:      "bold",
+	item.DIM:       "dim",
+	item.ITALIC:    "italics",
+	item.BLINK:     "blink",
+	item.REVERSE:   "reverse",
+	item.STANDOUT:  "reverse",
+	item.INVISIBLE: "hidden",
+	item.STRIKE:    "strikethrough",
+}
+
+// tmuxColors maps color attributes to their tmux style keys.
+var tmuxColors = map[item.Type]string{
+	item.FGCOLOR: "fg",
+	item.BGCOLOR: "bg",
+	item.ULCOLOR: "us",
+}
+
+// tmuxStyle returns the tmux style for attrs as a map of keys from tmuxKeys
+// to the style's value for that key.
+func tmuxStyle(attrs map[item.Type]string) map[string]string {
+	style := make(map[string]string)
+
+	for t, flag := range tmuxFlags {
+		if _, ok := attrs[t]; ok {
+			style[flag] = flag
+		}
+	}
+
+	if ul, ok := attrs[item.UNDERLINE]; ok {
+		switch ul {
+		case "double", "curly", "dotted", "dashed":
+			style["underscore"] = ul + "-underscore"
+		default:
+			style["underscore"] = "underscore"
+		}
+	}
+
+	for t, key := range tmuxColors {
+		clr, ok := attrs[t]
+		if !ok {
+			continue
+		}
+
+		if n, ok := paletteNumber(clr); ok {
+			style[key] = fmt.Sprintf("colour%d", n)
+		} else if rgb, ok := resolveColor(clr); ok {
+			style[key] = rgb.String()
+		}
+	}
+
+	return style
+}
+
+// tmuxChanges returns the comma separated list of tmux style changes needed
+// to move from style prev to style cur.
+func tmuxChanges(prev, cur map[string]string) string {
+	if len(cur) == 0 {
+		if len(prev) == 0 {
+			return ""
+		}
+		return "default"
+	}
+
+	var changes []string
+
+	for _, key := range tmuxKeys {
+		val, on := cur[key]
+		isColor := key == "fg" || key == "bg" || key == "us"
+
+		switch {
+		case on && val == prev[key]:
+			// unchanged
+		case on && isColor:
+			changes = append(changes, key+"="+val)
+		case on:
+			changes = append(changes, val)
+		case prev[key] == "":
+			// wasn't on either
+		case isColor:
+			changes = append(changes, key+"=default")
+		default:
+			changes = append(changes, "no"+key)
+		}
+	}
+
+	return strings.Join(changes, ",")
+}
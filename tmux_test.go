@@ -0,0 +1,24 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestTmux(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"#1 @Bbold@b", "##1 #[bold]bold#[default]"},
+		{"@B@F{44}@Iuser@i@f@F{Orchid1}@@@f@F{Green3}host@f@b", "#[fg=colour44,bold,italics]user#[fg=colour213,noitalics]@#[fg=colour40]host#[default]"},
+		{"@K{#ff8800}@Sa@s@U{curly}@C{Red1}b@c@u@k", "#[bg=#ff8800,reverse]a#[us=colour196,curly-underscore,noreverse]b#[default]"},
+		{"@F{RED}a@F{BLUE}b@fc@Xd@x", "#[fg=colour1]a#[fg=colour4]b#[fg=colour1]c#[strikethrough]d#[nostrikethrough]"},
+		{"@L{https://x.y}@Ulink@u@l", "#[underscore]link#[default]"},
+	}
+
+	for _, tc := range cases {
+		if got, err := (&Tmux{}).Format(tc.text); err != nil || got != tc.want {
+			t.Errorf("Tmux.Format(%q) == (%q, %v); Wanted (%q, nil)", tc.text, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Truncate shortens the given decor-notated text to at most width columns
+// (as measured by Width) including the decor-notated tail (e.g. "…") that is
+// appended whenever text is shortened. Text is never cut within a designator
+// and the tail is displayed with whatever attributes were in effect at the
+// cut point, after which all of those attributes are stopped. If text fits
+// within width it is returned unaltered.
+//
+// Variable references before the cut point are retained but, since their
+// values are unknown, take up no width. If tail is wider than width, the
+// result holds only tail. An error is returned if either text or tail cannot
+// be parsed as decor notation.
+func Truncate(text string, width int, tail string) (string, error) {
+	ss := series.New()
+	if err := ss.Parse(text); err != nil {
+		return "", err
+	}
+
+	ts := series.New()
+	if err := ts.Parse(tail); err != nil {
+		return "", err
+	}
+
+	expandTextTabs(ss)
+	expandTextTabs(ts)
+
+	if textWidth(ss) <= width {
+		return text, nil
+	}
+
+	avail := width - textWidth(ts)
+
+	var (
+		out   = series.New()
+		state = newAttrState()
+
+		// pending holds the non-TEXT items following the last text
+		// to fit; they're only retained if more text fits as well.
+		pending []*item.Item
+	)
+
+	keep := func(itms ...*item.Item) {
+		for _, itm := range itms {
+			out.Append(itm)
+			state.update(itm)
+		}
+		pending = nil
+	}
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type != item.TEXT {
+			pending = append(pending, itm.Detach())
+			continue
+		}
+
+		if w := stringWidth(itm.Text); w <= avail {
+			keep(append(pending, itm.Detach())...)
+			avail -= w
+			continue
+		}
+
+		if cut := cutWidth(itm.Text, avail); cut != "" {
+			keep(append(pending, item.TextItem(cut))...)
+		}
+
+		break
+	}
+
+	// Of the items at the cut point, only those stopping an attribute that
+	// was started before it are retained.
+	dropped := make(map[item.Type]int)
+	for _, itm := range pending {
+		switch {
+		case itm.Action == item.START:
+			dropped[itm.Type]++
+		case itm.Action == item.STOP && dropped[itm.Type] > 0:
+			dropped[itm.Type]--
+		case itm.Action == item.STOP:
+			out.Append(itm)
+			state.update(itm)
+		}
+	}
+
+	out.AppendList(ts)
+
+	// Stop each attribute still in effect, most recent first.
+	for a := state.active.Back(); a != nil; a = a.Prev() {
+		out.Append(item.StopItem(a.Type))
+	}
+
+	return out.Notation(), nil
+}
+
+// textWidth returns the width (as described for Width) of the text in ss.
+func textWidth(ss *series.Series) int {
+	var b strings.Builder
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type == item.TEXT {
+			b.WriteString(itm.Text)
+		}
+	}
+
+	return stringWidth(b.String())
+}
+
+// expandTextTabs replaces the tabs in each TEXT item of ss with spaces, as
+// if ss were displayed starting at column zero.
+func expandTextTabs(ss *series.Series) {
+	var col int
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type == item.TEXT {
+			itm.Text, col = expandTabs(itm.Text, col)
+		}
+	}
+}
+
+// cutWidth returns the longest prefix of s no wider than width. Characters
+// of zero width (e.g. combining marks) following the last character that
+// fits are retained.
+func cutWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	var n int
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		if stringWidth(s[:i+size]) > width {
+			break
+		}
+		i += size
+		n = i
+	}
+
+	return s[:n]
+}
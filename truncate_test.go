@@ -0,0 +1,44 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		text  string
+		width int
+		tail  string
+		want  string
+	}{
+		{"short", 10, "…", "short"},
+		{"exactly10!", 10, "…", "exactly10!"},
+		{"/home/user/src/project", 10, "…", "/home/use…"},
+		{"@F{Blue}/home/@Buser@b/src@f", 8, "…", "@F{Blue}/home/@Bu…@b@f"},
+		{"@F{Blue}/home/@Buser@b/src@f", 8, "@F{Grey50}...@f", "@F{Blue}/home@F{Grey50}...@f@f"},
+		{"@F{Red}a@F{Blue}bcdef@f@f", 3, "~", "@F{Red}a@F{Blue}b~@f@f"},
+		{"user@@host $$ more", 8, "", "user@@hos"},
+		{"日本語テキスト", 7, "…", "日本語…"},
+		{"@U{curly}x@u@Xtoolong@x", 3, "…", "@U{curly}x@u@Xt…@x"},
+		{"${name} is long", 5, ".", "${name} is ."},
+		{"@L{https://x.y}link text@l", 4, "", "@L{https://x.y}link@l"},
+		{"abc", 0, "…", "…"},
+		{"abc@Bdef@b", 4, "…", "abc…"},
+		{"@Babc@bdef", 4, "…", "@Babc@b…"},
+		{"ab@B@F{Red}日本@f@b", 4, "…", "ab…"},
+		{"@F{Red}abc@F{Blue}@fdef@f", 4, "…", "@F{Red}abc…@f"},
+		{"abc\nde", 3, "…", "abc\nde"},
+		{"a\tb", 9, "…", "a\tb"},
+		{"a\tbcdef", 10, "…", "a       b…"},
+	}
+
+	for _, tc := range cases {
+		if got, err := Truncate(tc.text, tc.width, tc.tail); err != nil || got != tc.want {
+			t.Errorf("Truncate(%q, %d, %q) == (%q, %v); Wanted (%q, nil)", tc.text, tc.width, tc.tail, got, err, tc.want)
+		}
+	}
+
+	if _, err := Truncate("@F{Red", 3, "…"); err == nil {
+		t.Errorf("Truncate(%q, 3, ...) succeeded; Wanted error", "@F{Red")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"fmt"
+
+	"github.com/xo/terminfo"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/colors"
+	"toolman.org/terminal/decor/internal/item"
+)
+
+const (
+	ansiULColor256 = "\x1b[58;5;%dm"
+	ansiDefULColor = "\x1b[59m"
+)
+
+// setUnderline populates the receiver's underline style codes from the
+// given Terminfo's "Smulx" capability (falling back to a plain underline
+// if it does not exist) and, if it has the "Setulc" capability, its table
+// of underline colors.
+func (d *Decorator) setUnderline(ti *terminfo.Terminfo) {
+	smulx := extStringCap(ti, "Smulx")
+
+	d.ulstyle = make(map[string]string, len(item.UnderlineStyles))
+	for i, name := range item.UnderlineStyles {
+		if smulx != nil {
+			d.ulstyle[name] = terminfo.Printf(smulx, i+1)
+		} else {
+			d.ulstyle[name] = d.enter[item.UNDERLINE]
+		}
+	}
+
+	if d.setulc = extStringCap(ti, "Setulc"); d.setulc != nil {
+		d.ul = make([]string, len(colors.Names))
+		for n := range d.ul {
+			d.ul[n] = fmt.Sprintf(ansiULColor256, n)
+		}
+	}
+}
+
+// underline returns the terminal code for starting an underline with the
+// given style. An empty style indicates a plain underline.
+func (d *Decorator) underline(style string) string {
+	if style == "" {
+		return d.enter[item.UNDERLINE]
+	}
+
+	if code, ok := d.ulstyle[style]; ok {
+		return code
+	}
+
+	return fmt.Sprintf("<!underline:%s>", style)
+}
+
+// ulColor returns the terminal code for setting the underline color to the
+// given color name, number or direct color specification -- or the empty
+// string if the receiver's terminal does not support underline colors (or
+// colors have been disabled).
+func (d *Decorator) ulColor(clr string) string {
+	if d.setulc == nil || d.colors == 0 {
+		return ""
+	}
+
+	return d.lookup(clr, d.ul, func(c color.RGB) string {
+		return terminfo.Printf(d.setulc, int(c.R)<<16|int(c.G)<<8|int(c.B))
+	})
+}
+
+// defULColor returns the terminal code for restoring the default underline
+// color, or the empty string if the receiver's terminal does not support
+// underline colors.
+func (d *Decorator) defULColor() string {
+	if d.setulc == nil || d.colors == 0 {
+		return ""
+	}
+	return ansiDefULColor
+}
@@ -0,0 +1,57 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"testing"
+
+	"github.com/xo/terminfo"
+)
+
+// addExtString adds an extended string capability to the given Terminfo.
+func addExtString(ti *terminfo.Terminfo, name, value string) {
+	k := len(ti.ExtStringNames) + len(ti.ExtBoolNames) + len(ti.ExtNumNames)
+	ti.ExtStringNames[k] = []byte(name)
+	ti.ExtStrings[k] = []byte(value)
+}
+
+func TestUnderline(t *testing.T) {
+	ti, err := xterm256Terminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := newDecorator("xterm-256color", ti)
+
+	addExtString(ti, "Smulx", "\x1b[4:%p1%dm")
+	addExtString(ti, "Setulc", "\x1b[58:2::%p1%{65536}%/%d:%p1%{256}%/%{255}%&%d:%p1%{255}%&%dm")
+
+	ext := newDecorator("xterm-kitty", ti)
+	ext.colors = DirectColors
+
+	cases := []struct {
+		label string
+		dec   *Decorator
+		input string
+		want  string
+	}{
+		{"plain", plain, "@Ux@u", "\x1b[4mx\x1b[24m"},
+		{"plain-curly", plain, "@U{curly}x@u", "\x1b[4mx\x1b[24m"},
+		{"plain-color", plain, "@C{Red}@Ux@u@c", "\x1b[4mx\x1b[24m"},
+		{"ext-curly", ext, "@U{curly}x@u", "\x1b[4:3mx\x1b[24m"},
+		{"ext-double", ext, "@U{double}x@u", "\x1b[4:2mx\x1b[24m"},
+		{"ext-nested", ext, "@U{curly}a@U{dotted}b@uc@u", "\x1b[4:3ma\x1b[4:4mb\x1b[4:3mc\x1b[24m"},
+		{"ext-unknown", ext, "@U{wavy}x@u", "\x1b[4m{wavy}x\x1b[24m"},
+		{"ext-empty", ext, "@U{}x@u", "\x1b[4m{}x\x1b[24m"},
+		{"ext-color", ext, "@C{Red}@U{curly}x@u@c", "\x1b[58;5;1m\x1b[4:3mx\x1b[24m\x1b[59m"},
+		{"ext-direct", ext, "@C{#ff8800}@Ux@u@c", "\x1b[58:2::255:136:0m\x1b[4mx\x1b[24m\x1b[59m"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			if got, err := tc.dec.Format(tc.input); err != nil || got != tc.want {
+				t.Errorf("Format(%q) == (%q, %v); Wanted (%q, nil)", tc.input, got, err, tc.want)
+			}
+		})
+	}
+}
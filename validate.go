@@ -0,0 +1,128 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"strconv"
+	"strings"
+
+	"toolman.org/terminal/decor/color"
+	"toolman.org/terminal/decor/internal/colors"
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Validate checks that text is well-formed decor notation using only known
+// attribute designators and color names (or numbers, or direct colors). A
+// *ParseError is returned for each problem found, with a suggested
+// replacement for near-miss color names. A nil slice is returned if text is
+// valid.
+//
+// The default and alternate values of conditional variable references (i.e.
+// "${name:-word}" and "${name:+word}") are checked as well.
+//
+// Note that text following a syntax error (e.g. an unterminated attribute)
+// cannot be checked.
+func Validate(text string) []error {
+	return series.Validate(text, check)
+}
+
+// parse parses text into ss -- rejecting unknown designators and colors if
+// the receiver is strict (see the Strict Option).
+func (d *Decorator) parse(ss *series.Series, text string) error {
+	if d != nil && d.strict {
+		return ss.ParseStrict(text, check)
+	}
+	return ss.Parse(text)
+}
+
+// check is a series.Checker that rejects unknown attributes and colors.
+func check(itm *item.Item) (series.ErrorKind, string) {
+	switch {
+	case itm.Type == item.EMPTY:
+		return series.UnknownAttribute, ""
+
+	case itm.Action == item.STOP:
+		return 0, ""
+
+	case itm.Type == item.FGCOLOR, itm.Type == item.BGCOLOR, itm.Type == item.ULCOLOR:
+		if _, ok := resolveColor(itm.Text); !ok {
+			return series.UnknownColor, suggest(itm.Text, colors.Names)
+		}
+	}
+
+	return 0, ""
+}
+
+// resolveColor returns the RGB value for clr, which may be a color name, a
+// color number or a direct color specification. If clr cannot be resolved,
+// false is returned.
+func resolveColor(clr string) (color.RGB, bool) {
+	if n := color.Number(clr); n >= 0 {
+		return color.Value(uint8(n)), true
+	}
+
+	if n, err := strconv.Atoi(clr); err == nil {
+		if n < 0 || n >= len(colors.Names) {
+			return color.RGB{}, false
+		}
+		return color.Value(uint8(n)), true
+	}
+
+	rgb, err := color.ParseRGB(clr)
+	return rgb, err == nil
+}
+
+// suggest returns the candidate nearest to s (ignoring case), or the empty
+// string if none are near enough to be a likely replacement.
+func suggest(s string, candidates []string) string {
+	var best string
+
+	// Allow roughly one edit for every 3 characters.
+	bestD := len(s)/3 + 2
+	ls := strings.ToLower(s)
+
+	for _, c := range candidates {
+		if d := editDistance(ls, strings.ToLower(c)); d < bestD {
+			best, bestD = c, d
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
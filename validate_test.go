@@ -0,0 +1,78 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"@B@F{Orchid1}@U{curly}@C{#ff8800}text@c@u@f@b", nil},
+		{"@K{200}@F{rgb(1,2,3)}x@f@k", nil},
+		{"@Zfoo@z", []string{
+			"unknown attribute @Z at pos 0",
+			"unknown attribute @z at pos 5",
+		}},
+		{"@F{Orhcid1}x@f@K{300}y@k", []string{
+			`unknown color "Orhcid1" for @F at pos 0 (did you mean "Orchid1"?)`,
+			`unknown color "300" for @K at pos 14`,
+		}},
+		{"@U{note}x@u@U{}@u", nil},
+		{"@C{Bogus}x@Q@F{Red", []string{
+			`unknown color "Bogus" for @C at pos 0`,
+			"unknown attribute @Q at pos 10",
+			"unterminated attribute @F at pos 12",
+		}},
+		{"${x:-@F{Bogus}y@f}${z:+@Q}${w:?@Z}", []string{
+			`unknown color "Bogus" for @F at pos 5`,
+			"unknown attribute @Q at pos 23",
+		}},
+		{"${x:-@B${y:-@Q}}", []string{
+			"unknown attribute @Q at pos 12",
+		}},
+		{"${x:-a$b}@Z", []string{
+			"malformed variable reference at pos 6",
+		}},
+	}
+
+	for _, tc := range cases {
+		var got []string
+		for _, err := range Validate(tc.text) {
+			got = append(got, err.Error())
+		}
+
+		if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Errorf("Validate(%q) == %q; Wanted %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "@F{Gren3}x@f"
+
+	if got, err := d.Format(text); err != nil || got != "<!color:Gren3>x"+xt_defFG {
+		t.Errorf("Format(%q) == (%q, %v); Wanted lenient output", text, got, err)
+	}
+
+	d.apply([]Option{Strict()})
+
+	got, err := d.Format(text)
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Kind != UnknownColor || pe.Suggestion != "Green3" {
+		t.Errorf("Format(%q) == (%q, %v); Wanted UnknownColor *ParseError suggesting %q", text, got, err, "Green3")
+	}
+
+	if _, err := d.Template("@Bx@Jy@b"); err == nil {
+		t.Errorf("Template(%q) succeeded; Wanted UnknownAttribute error", "@Bx@Jy@b")
+	}
+}
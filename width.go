@@ -0,0 +1,253 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Width returns the number of terminal columns needed to display the given
+// decor-notated text, ignoring all attribute designators. For text spanning
+// multiple lines, this is the width of its widest line. East Asian wide (and
+// fullwidth) characters occupy two columns while combining marks, zero width
+// characters and other control characters occupy none. Tabs advance to the
+// next tab stop, which are every 8 columns from the start of each line. Emoji
+// sequences joined with a zero width joiner (U+200D) are counted as a single
+// character, as are emoji with skin tone modifiers and regional indicator
+// pairs (i.e. flags).
+//
+// Since tab stops depend on where text is displayed, functions that move text
+// to other columns (i.e. Truncate, Wrap, Pad and Table) replace each tab with
+// the spaces needed to reach its tab stop.
+//
+// If text cannot be parsed as decor notation, the width of text itself is
+// returned.
+func Width(text string) int {
+	s, err := Strip(text)
+	if err != nil {
+		s = text
+	}
+
+	return stringWidth(s)
+}
+
+// Width returns the number of terminal columns needed to display the given
+// formatted output (e.g. as returned by the receiver's Format method) with
+// all terminal codes ignored. If the receiver has the PromptShell Option,
+// the shell's zero-width markers are ignored as well. Characters are
+// measured as described for the Width function.
+func (d *Decorator) Width(formatted string) int {
+	var (
+		b     strings.Builder
+		shell Shell
+		start string
+		end   string
+	)
+
+	if d != nil {
+		shell = d.shell
+	}
+
+	switch shell {
+	case Zsh:
+		start, end = "%{", "%}"
+	case Bash:
+		start, end = `\[`, `\]`
+	}
+
+	for s := formatted; s != ""; {
+		switch {
+		case start != "" && strings.HasPrefix(s, start):
+			s = s[len(start):]
+		case end != "" && strings.HasPrefix(s, end):
+			s = s[len(end):]
+		case s[0] == '\x1b':
+			s = s[escapeLen(s):]
+		default:
+			b.WriteByte(s[0])
+			s = s[1:]
+		}
+	}
+
+	return stringWidth(b.String())
+}
+
+// escapeLen returns the length of the terminal escape sequence at the start
+// of s, which must begin with an ESC character.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+
+	switch s[1] {
+	case '[':
+		// Control Sequence: parameters end with a byte from '@' to '~'
+		if i := strings.IndexFunc(s[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e }); i != -1 {
+			return i + 3
+		}
+		return len(s)
+
+	case ']':
+		// Operating System Command: ends with BEL or ST (ESC \)
+		i := strings.IndexAny(s[2:], "\a\x1b")
+		switch {
+		case i == -1, i+4 > len(s):
+			return len(s)
+		case s[2+i] == '\a':
+			return i + 3
+		default:
+			return i + 4
+		}
+
+	case '(', ')', '*', '+':
+		// Character set designation
+		if len(s) < 3 {
+			return len(s)
+		}
+		return 3
+
+	default:
+		return 2
+	}
+}
+
+// stringWidth returns the number of terminal columns needed to display s
+// (i.e. the width of its widest line).
+func stringWidth(s string) int {
+	var (
+		w      int
+		col    int
+		joined bool
+		ri     bool
+	)
+
+	for _, r := range s {
+		switch {
+		case joined:
+			// Joined to the previous character by U+200D
+			joined = false
+
+		case r == '\u200d':
+			joined = true
+
+		case r == '\t':
+			col += tabWidth - col%tabWidth
+
+		case r == '\n':
+			col = 0
+
+		case r >= 0x1f3fb && r <= 0x1f3ff:
+			// Emoji skin tone modifiers
+
+		case r >= 0x1f1e6 && r <= 0x1f1ff:
+			// Regional indicators, which pair up to display a flag
+			if ri = !ri; ri {
+				col += 2
+			}
+
+		default:
+			col += runeWidth(r)
+		}
+
+		if r < 0x1f1e6 || r > 0x1f1ff {
+			ri = false
+		}
+
+		if col > w {
+			w = col
+		}
+	}
+
+	return w
+}
+
+// tabWidth is the distance between tab stops.
+const tabWidth = 8
+
+// expandTabs returns s with each tab replaced by the spaces needed to reach
+// the next tab stop when s is displayed starting at column col, along with
+// the column following s.
+func expandTabs(s string, col int) (string, int) {
+	var b strings.Builder
+
+	for {
+		i := strings.IndexAny(s, "\t\n")
+		if i == -1 {
+			b.WriteString(s)
+			return b.String(), col + stringWidth(s)
+		}
+
+		b.WriteString(s[:i])
+
+		if s[i] == '\n' {
+			b.WriteByte('\n')
+			col = 0
+		} else {
+			col += stringWidth(s[:i])
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		}
+
+		s = s[i+1:]
+	}
+}
+
+// runeWidth returns the number of terminal columns needed to display r.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7f && r < 0xa0:
+		return 0
+	case r >= 0x1160 && r <= 0x11ff:
+		// Hangul medial vowels and final consonants
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// isWide returns true if r is an East Asian wide or fullwidth character
+// (including most emoji).
+func isWide(r rune) bool {
+	if r < wideRanges[0][0] {
+		return false
+	}
+
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+
+	return i < len(wideRanges) && r >= wideRanges[i][0]
+}
+
+// wideRanges are the (sorted, inclusive) ranges of East Asian wide and
+// fullwidth characters.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18cff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f202}, {0x1f210, 0x1f23b},
+	{0x1f240, 0x1f248}, {0x1f250, 0x1f251}, {0x1f260, 0x1f265}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
@@ -0,0 +1,58 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	cases := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"@B@F{44}user@f@@host@b", 9},
+		{"héllo", 5},
+		{"é", 1},
+		{"日本語", 6},
+		{"@K{Red1}ｆｕｌｌ@k", 8},
+		{"👍🏽 ok", 5},
+		{"👨‍👩‍👧!", 3},
+		{"🇯🇵🇺🇸", 4},
+		{"a\tb\n", 9},
+		{"@Bab@b\tc", 9},
+		{"\t\tx", 17},
+		{"日本語\tx", 9},
+		{"abc\n\td", 9},
+		{"abc\nde", 3},
+		{"ab\n@Bcdef@b\n", 4},
+		{"@F{unterminated", 15},
+	}
+
+	for _, tc := range cases {
+		if got := Width(tc.text); got != tc.want {
+			t.Errorf("Width(%q) == %d; Wanted %d", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestDecoratorWidth(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "@B@L{https://x.y}@U{curly}日本@u@l@b語 @F{#ff8800}x@f"
+
+	for _, opts := range [][]Option{nil, {MergeSGR()}, {Hyperlinks(true)}, {PromptShell(Zsh)}, {PromptShell(Bash)}} {
+		d.apply(opts)
+
+		out, err := d.Format(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := d.Width(out); got != 8 {
+			t.Errorf("Width(%q) == %d; Wanted 8", out, got)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Wrap word-wraps the given decor-notated text so that no line is wider than
+// width columns (as measured by Width), breaking lines between words where
+// possible and within words only when a single word is wider than width.
+// Whitespace at each line break added by Wrap is removed. Attributes in
+// effect at the end of each line are stopped before the line break and
+// started again at the beginning of the next line so that attributes (e.g.
+// background colors) don't bleed into the margin. Existing line breaks in
+// text are retained and treated similarly (but whitespace preceding them is
+// kept if it fits). Variable references take up no width.
+//
+// An error is returned if text cannot be parsed as decor notation.
+func Wrap(text string, width int) (string, error) {
+	ss := series.New()
+	if err := ss.Parse(text); err != nil {
+		return "", err
+	}
+
+	return joinLines(wrapLines(ss, width)), nil
+}
+
+// Alignment specifies how Pad aligns text within a given width.
+type Alignment int
+
+const (
+	// AlignLeft pads text on the right.
+	AlignLeft Alignment = iota
+
+	// AlignRight pads text on the left.
+	AlignRight
+
+	// AlignCenter pads text evenly on both sides (with any odd space on
+	// the right).
+	AlignCenter
+)
+
+// Pad aligns each line of the given decor-notated text within width columns
+// (as measured by Width) by adding spaces according to align. As with Wrap,
+// attributes are stopped at the end of each line (and started again on the
+// next) with padding added outside of any attributes so, e.g., background
+// colors only apply to the text itself. Lines already at least width columns
+// wide are left as-is.
+//
+// An error is returned if text cannot be parsed as decor notation.
+func Pad(text string, width int, align Alignment) (string, error) {
+	ss := series.New()
+	if err := ss.Parse(text); err != nil {
+		return "", err
+	}
+
+	return joinLines(padLines(wrapLines(ss, 0), width, align)), nil
+}
+
+// padLines pads each of lines to width according to align.
+func padLines(lines []*series.Series, width int, align Alignment) []*series.Series {
+	for i, line := range lines {
+		pad := width - textWidth(line)
+		if pad <= 0 {
+			continue
+		}
+
+		var left, right int
+		switch align {
+		case AlignRight:
+			left = pad
+		case AlignCenter:
+			left = pad / 2
+			right = pad - left
+		default:
+			right = pad
+		}
+
+		padded := series.New()
+		if left > 0 {
+			padded.Append(item.TextItem(strings.Repeat(" ", left)))
+		}
+
+		padded.AppendList(line)
+
+		if right > 0 {
+			padded.Append(item.TextItem(strings.Repeat(" ", right)))
+		}
+
+		lines[i] = padded
+	}
+
+	return lines
+}
+
+// joinLines returns the decor notation for lines joined by newlines.
+func joinLines(lines []*series.Series) string {
+	var parts []string
+
+	for _, line := range lines {
+		parts = append(parts, line.Notation())
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// wrapLines splits ss into self contained lines (i.e. with all attributes
+// stopped at the end of each line and restarted at the beginning of the
+// next) no wider than width. A width of zero or less splits ss only at its
+// existing line breaks.
+func wrapLines(ss *series.Series, width int) []*series.Series {
+	w := &wrapper{
+		width: width,
+		lines: []*series.Series{series.New()},
+		state: newAttrState(),
+	}
+
+	for itm := ss.Front(); itm != nil; itm = itm.Next() {
+		if itm.Type == item.TEXT {
+			w.addText(itm.Text)
+		} else {
+			w.word = append(w.word, itm.Detach())
+		}
+	}
+
+	w.flushWord()
+	w.flushSpace()
+
+	return w.lines
+}
+
+// wrapper holds the state for wrapLines.
+type wrapper struct {
+	width int
+	lines []*series.Series
+	state *attrState
+
+	// col is the width of the current line.
+	col int
+
+	// space holds whitespace preceding the pending word.
+	space string
+
+	// word holds the pending word's text along with any attribute (or
+	// other) items within or immediately preceding it; wordW is its width.
+	word  []*item.Item
+	wordW int
+}
+
+// fits returns true if n more columns fit on the current line.
+func (w *wrapper) fits(n int) bool {
+	return w.width <= 0 || w.col+n <= w.width
+}
+
+func (w *wrapper) addText(text string) {
+	for text != "" {
+		r, size := utf8.DecodeRuneInString(text)
+
+		switch {
+		case r == '\n':
+			w.flushWord()
+			w.flushSpace()
+			w.breakLine()
+
+		case unicode.IsSpace(r):
+			if len(w.word) != 0 {
+				w.flushWord()
+			}
+			w.space += string(r)
+
+		default:
+			// Extend the pending word with this (and any following
+			// non-space) text.
+			i := strings.IndexFunc(text, unicode.IsSpace)
+			if i == -1 {
+				i = len(text)
+			}
+
+			w.word = append(w.word, item.TextItem(text[:i]))
+			w.wordW += stringWidth(text[:i])
+			size = i
+		}
+
+		text = text[size:]
+	}
+}
+
+// flushWord adds the pending word (and any preceding whitespace) to the
+// current line, or to a new line if it doesn't fit. Words too wide for even
+// a line of their own are split across lines.
+func (w *wrapper) flushWord() {
+	if len(w.word) == 0 {
+		return
+	}
+
+	space, _ := expandTabs(w.space, w.col)
+	sw := stringWidth(space)
+
+	switch {
+	case w.col > 0 && !w.fits(sw+w.wordW):
+		w.breakLine()
+	case space != "":
+		w.emit(item.TextItem(space))
+		w.col += sw
+	}
+
+	w.space = ""
+
+	for _, itm := range w.word {
+		if itm.Type != item.TEXT {
+			w.emit(itm)
+			continue
+		}
+
+		for text := itm.Text; text != ""; {
+			fit := text
+			if !w.fits(stringWidth(text)) {
+				if fit = cutWidth(text, w.width-w.col); fit == "" && w.col == 0 {
+					// Not even a single character fits; force
+					// one onto the line anyway.
+					_, size := utf8.DecodeRuneInString(text)
+					fit = text[:size]
+				}
+			}
+
+			if fit == "" {
+				w.breakLine()
+				continue
+			}
+
+			w.emit(item.TextItem(fit))
+			w.col += stringWidth(fit)
+			text = text[len(fit):]
+		}
+	}
+
+	w.word = nil
+	w.wordW = 0
+}
+
+// flushSpace adds any whitespace following the final word on a line to the
+// end of that line, so long as it fits.
+func (w *wrapper) flushSpace() {
+	if space, _ := expandTabs(w.space, w.col); space != "" && w.fits(stringWidth(space)) {
+		w.emit(item.TextItem(space))
+		w.col += stringWidth(space)
+	}
+
+	w.space = ""
+}
+
+// emit appends itm to the current line, tracking any attribute changes.
+func (w *wrapper) emit(itm *item.Item) {
+	w.lines[len(w.lines)-1].Append(itm)
+	w.state.update(itm)
+}
+
+// breakLine stops all attributes in effect at the end of the current line
+// and starts a new line with those same attributes in effect.
+func (w *wrapper) breakLine() {
+	line := w.lines[len(w.lines)-1]
+	next := series.New()
+
+	for a := w.state.active.Back(); a != nil; a = a.Prev() {
+		line.Append(item.StopItem(a.Type))
+	}
+
+	for a := w.state.active.Front(); a != nil; a = a.Next() {
+		next.Append(a.Detach())
+	}
+
+	w.lines = append(w.lines, next)
+	w.col = 0
+	w.space = ""
+}
@@ -0,0 +1,57 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	cases := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"the quick brown fox", 20, "the quick brown fox"},
+		{"the quick brown fox", 10, "the quick\nbrown fox"},
+		{"the  quick   brown fox", 10, "the  quick\nbrown fox"},
+		{"@K{Blue}the quick brown fox@k", 10, "@K{Blue}the quick@k\n@K{Blue}brown fox@k"},
+		{"a @Bbold@b word", 6, "a @Bbold@b\nword"},
+		{"@F{Red}x @F{Blue}yy zz@f w@f", 5, "@F{Red}x @F{Blue}yy@f@f\n@F{Red}@F{Blue}zz@f w@f"},
+		{"abcdefghij", 4, "abcd\nefgh\nij"},
+		{"ab @Ucdefgh@u", 4, "ab\n@Ucdef@u\n@Ugh@u"},
+		{"日本語のテキスト", 5, "日本\n語の\nテキ\nスト"},
+		{"one\n@Btwo three@b", 5, "one\n@Btwo@b\n@Bthree@b"},
+		{"user@@host $$x", 6, "user@@h\nost $$x"},
+		{"a\tb c\td", 10, "a       b\nc       d"},
+		{"ab  \ncd", 10, "ab  \ncd"},
+	}
+
+	for _, tc := range cases {
+		if got, err := Wrap(tc.text, tc.width); err != nil || got != tc.want {
+			t.Errorf("Wrap(%q, %d) == (%q, %v); Wanted (%q, nil)", tc.text, tc.width, got, err, tc.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	cases := []struct {
+		text  string
+		width int
+		align Alignment
+		want  string
+	}{
+		{"abc", 6, AlignLeft, "abc   "},
+		{"abc", 6, AlignRight, "   abc"},
+		{"abc", 6, AlignCenter, " abc  "},
+		{"abcdef", 4, AlignRight, "abcdef"},
+		{"a\tb", 12, AlignRight, "   a       b"},
+		{"ab  \ncd", 6, AlignRight, "  ab  \n    cd"},
+		{"@K{Red1}日本@k", 6, AlignRight, "  @K{Red1}日本@k"},
+		{"@K{Red1}ab\ncdef@k", 5, AlignCenter, " @K{Red1}ab@k  \n@K{Red1}cdef@k "},
+	}
+
+	for _, tc := range cases {
+		if got, err := Pad(tc.text, tc.width, tc.align); err != nil || got != tc.want {
+			t.Errorf("Pad(%q, %d, %d) == (%q, %v); Wanted (%q, nil)", tc.text, tc.width, tc.align, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"io"
+
+	"toolman.org/terminal/decor/internal/item"
+	"toolman.org/terminal/decor/internal/series"
+)
+
+// Writer is an io.WriteCloser that formats decor-notated text written to it
+// and writes the result to an underlying io.Writer. Text may be written in
+// arbitrary chunks -- even those splitting a designator across multiple
+// writes -- and attribute state is retained from one write to the next.
+type Writer struct {
+	w       io.Writer
+	opt     *optimizer
+	pending string
+}
+
+// NewWriter returns a new *Writer that formats decor-notated text using the
+// Decorator d and writes the result to w. The returned Writer's Close method
+// should be called once all text has been written to turn off any attributes
+// still in effect.
+func NewWriter(w io.Writer, d *Decorator) *Writer {
+	return &Writer{w: w, opt: d.optimizer()}
+}
+
+// Write formats as much of p (along with any text retained from previous
+// calls) as possible and writes the result to the underlying io.Writer. Any
+// trailing partial designator is retained until it's completed by a later
+// call to Write. An error is returned if the text cannot be parsed as decor
+// notation or the underlying io.Writer returns an error.
+func (w *Writer) Write(p []byte) (int, error) {
+	text := w.pending + string(p)
+	n := series.Complete(text)
+
+	ss := series.New()
+	if err := w.opt.parse(ss, text[:n]); err != nil {
+		return 0, err
+	}
+
+	w.pending = text[n:]
+
+	mark := w.opt.output.Back()
+	w.opt.add(ss)
+
+	if err := w.flush(mark); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close turns off all attributes still in effect. An error is returned if a
+// partial designator remains from the final call to Write. Note that Close
+// does not close the underlying io.Writer.
+func (w *Writer) Close() error {
+	ss := series.New()
+
+	if w.pending != "" {
+		if err := w.opt.parse(ss, w.pending); err != nil {
+			return err
+		}
+		w.pending = ""
+	}
+
+	mark := w.opt.output.Back()
+	w.opt.add(ss)
+	w.opt.stopAll()
+
+	return w.flush(mark)
+}
+
+// flush formats the optimizer's output following mark and writes the result
+// to the underlying io.Writer. Afterward, the optimizer's output is reduced
+// to only those attributes currently in effect (since these are all that are
+// needed to optimize subsequent writes).
+func (w *Writer) flush(mark *item.Item) error {
+	out := w.opt.format(w.opt.output.After(mark))
+
+	w.opt.output = w.opt.effective()
+
+	_, err := io.WriteString(w.w, out)
+	return err
+}
+
+// stopAll turns off all active attributes (in the reverse order they were
+// started) while discarding any restore points.
+func (o *optimizer) stopAll() {
+	tops := o.active.Topmost()
+
+	o.active = series.New()
+	o.restorePoints = seriesStack{}
+
+	for a := tops.Back(); a != nil; a = a.Prev() {
+		o.emit(o.output, o.active, item.StopItem(a.Type))
+	}
+}
+
+// effective returns a new Series holding the attribute STARTs currently in
+// effect at the end of the receiver's output followed by an empty TEXT Item
+// which prevents these from being formatted again or altered by subsequent
+// optimization.
+func (o *optimizer) effective() *series.Series {
+	var items []*item.Item
+	seen := make(map[item.Type]bool)
+
+	var allOff bool
+	for itm := o.output.Back(); itm != nil; itm = itm.Prev() {
+		if itm.Action == item.NONE || seen[itm.Type] || allOff && isSGR(itm.Type) {
+			continue
+		}
+
+		seen[itm.Type] = true
+
+		if itm.Action == item.START && itm.Type != item.SAVE {
+			items = append([]*item.Item{itm}, items...)
+		}
+
+		if o.isAllOff(itm) {
+			allOff = true
+		}
+	}
+
+	return series.Build(items...).Append(item.TextItem(""))
+}
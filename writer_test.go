@@ -0,0 +1,90 @@
+// Copyright © 2023 Timothy E. Peoples
+
+package decor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		label  string
+		chunks []string
+	}{
+		{"whole", []string{"@B@F{44}@Iuser@i@F{Orchid1}@@@F{Green3}host@f@f@f@b"}},
+		{"split-designator", []string{"@B@F{4", "4}@Iuser@", "i@F{Orchid1}@", "@@F{Green3}host@f@f@f@b"}},
+		{"bytewise", []string{"@", "B", "@", "F", "{", "4", "4", "}", "@", "I", "user", "@", "i@F{Orchid1}@@@F{Green3}ho", "st@f@f@f@", "b"}},
+		{"restore-across-writes", []string{"@I@F{44}", "@Ba", "@b", "b@f@i"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.label, func(t *testing.T) {
+			var input string
+			for _, c := range tc.chunks {
+				input += c
+			}
+
+			want, err := d.Format(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			w := NewWriter(&buf, d)
+
+			for _, c := range tc.chunks {
+				if n, err := w.Write([]byte(c)); err != nil || n != len(c) {
+					t.Fatalf("w.Write(%q) == (%d, %v); Wanted (%d, nil)", c, n, err, len(c))
+				}
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("w.Close() error: %v", err)
+			}
+
+			if got := buf.String(); got != want {
+				t.Errorf("Writer output:\n   Got: %q\nWanted: %q", decodeAttrString(got), decodeAttrString(want))
+			}
+		})
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	d, err := xterm256Decorator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, d)
+
+	for _, c := range []string{"@F{Red}@Ia", "b@F{Blue}c"} {
+		if _, err := w.Write([]byte(c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\x1b[31m\x1b[3mab\x1b[34mc" + xt_defFG + xt_ritm
+	if got := buf.String(); got != want {
+		t.Errorf("Writer output:\n   Got: %q\nWanted: %q", decodeAttrString(got), decodeAttrString(want))
+	}
+
+	w = NewWriter(&buf, d)
+	if _, err := w.Write([]byte("abc@F{Red")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err == nil {
+		t.Errorf("w.Close() with partial designator: expected error")
+	}
+}